@@ -0,0 +1,66 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"syscall"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/lib/pq"
+	"github.com/valyala/fasthttp"
+)
+
+// classes of failed requests
+const (
+	ClassTimeout           = "timeout"
+	ClassNetwork           = "network"
+	ClassPartialWrite      = "partial write"
+	ClassFieldTypeConflict = "field type conflict"
+	ClassOther             = "other"
+)
+
+// Classify return class of a failed request, so failures could be aggregated:
+// timeout, network, field type conflict or partial write reported by influxdb,
+// "mysql <errno>", "postgres <sqlstate>", "http <status>", otherwise other
+func Classify(statusCode int, body string, err error) string {
+	if IsTimeout(err) {
+		return ClassTimeout
+	}
+	var myErr *mysql.MySQLError
+	if errors.As(err, &myErr) {
+		return fmt.Sprintf("mysql %d", myErr.Number)
+	}
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		return fmt.Sprintf("postgres %s", pqErr.Code)
+	}
+	if isNetwork(err) {
+		// sql clients report status 204 along with a broken connection
+		return ClassNetwork
+	}
+	switch {
+	case strings.Contains(body, "field type conflict"):
+		return ClassFieldTypeConflict
+	case strings.Contains(body, "partial write"):
+		return ClassPartialWrite
+	case statusCode >= 300:
+		return fmt.Sprintf("http %d", statusCode)
+	}
+	return ClassOther
+}
+
+// isNetwork check if err is caused by connection failure
+func isNetwork(err error) bool {
+	var netErr net.Error
+	return errors.As(err, &netErr) ||
+		errors.Is(err, io.EOF) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.EPIPE) ||
+		errors.Is(err, fasthttp.ErrConnectionClosed) ||
+		errors.Is(err, mysql.ErrInvalidConn)
+}
@@ -1,14 +1,64 @@
 package client
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
 	"github.com/deltacat/dbstress/config"
+	"github.com/valyala/fasthttp"
 )
 
-// Client db connection client interface
+// ErrTimeout request not answered within timeout of connection
+var ErrTimeout = errors.New("request timeout")
+
+// StatusError request answered with a failure status code
+type StatusError struct {
+	StatusCode int
+	RetryAfter time.Duration // delay asked by Retry-After header of the response, 0 if not asked
+}
+
+func (e *StatusError) Error() string {
+	return http.StatusText(e.StatusCode)
+}
+
+// RetryAfter return delay asked by the server before retrying the failed request, 0 if not asked
+func RetryAfter(err error) time.Duration {
+	var se *StatusError
+	if errors.As(err, &se) {
+		return se.RetryAfter
+	}
+	return 0
+}
+
+// parseRetryAfter parse Retry-After header, either delay in seconds or http date, 0 if invalid
+func parseRetryAfter(v string, now time.Time) time.Duration {
+	if v == "" {
+		return 0
+	}
+	if sec, err := strconv.Atoi(v); err == nil {
+		if sec < 0 {
+			return 0
+		}
+		return time.Duration(sec) * time.Second
+	}
+	if t, err := http.ParseTime(v); err == nil && t.After(now) {
+		return t.Sub(now)
+	}
+	return 0
+}
+
+// Client db connection client interface.
+// Requests give up when ctx is done, or after timeout of the connection.
 type Client interface {
 	Create(cmd string) error
-	Send(b []byte, gzip int) (latNs int64, statusCode int, body string, err error)
-	SendString(query string) (latNs int64, statusCode int, body string, err error)
+	Send(ctx context.Context, b []byte, gzip int) (latNs int64, statusCode int, body string, err error)
+	SendString(ctx context.Context, query string) (latNs int64, statusCode int, body string, err error)
+	Query(ctx context.Context, query string) (latNs int64, statusCode int, body string, err error)
 	Close() error
 	Reset() error
 	Name() string
@@ -20,3 +70,38 @@ type InfluxConfig = config.InfluxClientConfig
 
 // MySQLConfig mysql client config
 type MySQLConfig = config.MySQLClientConfig
+
+// LineProtocolConfig generic line protocol client config
+type LineProtocolConfig = config.LineProtocolClientConfig
+
+// PostgresConfig postgres client config
+type PostgresConfig = config.PostgresClientConfig
+
+// IsTimeout check if err is caused by request timeout
+func IsTimeout(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, ErrTimeout) || errors.Is(err, context.DeadlineExceeded) || errors.Is(err, fasthttp.ErrTimeout) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// withTimeout derive context of a request, no timeout if timeout is not positive
+func withTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// timeoutError mark err of a sql request as timeout if ctx of the request passed its deadline,
+// drivers report that differently, e.g. as "canceling statement" or "invalid connection"
+func timeoutError(ctx context.Context, err error) error {
+	if err == nil || IsTimeout(err) || ctx.Err() != context.DeadlineExceeded {
+		return err
+	}
+	return fmt.Errorf("%w: %v", ErrTimeout, err)
+}
@@ -1,6 +1,138 @@
 package client
 
-import "testing"
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/lib/pq"
+	"github.com/valyala/fasthttp"
+)
 
 func TestNewClient(t *testing.T) {}
 func TestSend(t *testing.T)      {}
+
+func TestIsTimeout(t *testing.T) {
+	tests := []struct {
+		err error
+		exp bool
+	}{
+		{nil, false},
+		{errors.New("refused"), false},
+		{context.Canceled, false},
+		{ErrTimeout, true},
+		{fmt.Errorf("write: %w", ErrTimeout), true},
+		{context.DeadlineExceeded, true},
+		{fasthttp.ErrTimeout, true},
+		{&net.OpError{Op: "read", Err: netTimeoutError{}}, true},
+	}
+	for _, tt := range tests {
+		if got := IsTimeout(tt.err); got != tt.exp {
+			t.Errorf("Wrong timeout of %v. Got %v, Expected: %v\n", tt.err, got, tt.exp)
+		}
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2021, 2, 1, 8, 0, 0, 0, time.UTC)
+	tests := []struct {
+		v   string
+		exp time.Duration
+	}{
+		{"", 0},
+		{"3", 3 * time.Second},
+		{"-1", 0},
+		{"Mon, 01 Feb 2021 08:00:10 GMT", 10 * time.Second},
+		{"Mon, 01 Feb 2021 07:59:00 GMT", 0},
+		{"soon", 0},
+	}
+	for _, tt := range tests {
+		if got := parseRetryAfter(tt.v, now); got != tt.exp {
+			t.Errorf("Wrong delay of %q. Got %v, Expected: %v\n", tt.v, got, tt.exp)
+		}
+	}
+	if got, exp := RetryAfter(fmt.Errorf("write: %w", &StatusError{StatusCode: 429, RetryAfter: time.Second})), time.Second; got != exp {
+		t.Errorf("Wrong retry after. Got %v, Expected: %v\n", got, exp)
+	}
+}
+
+type netTimeoutError struct{}
+
+func (netTimeoutError) Error() string   { return "i/o timeout" }
+func (netTimeoutError) Timeout() bool   { return true }
+func (netTimeoutError) Temporary() bool { return true }
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		status int
+		body   string
+		err    error
+		exp    string
+	}{
+		{0, "", ErrTimeout, ClassTimeout},
+		{0, "", &net.OpError{Op: "dial", Err: errors.New("connection refused")}, ClassNetwork},
+		{0, "", fmt.Errorf("write: %w", io.EOF), ClassNetwork},
+		{204, "", mysql.ErrInvalidConn, ClassNetwork},
+		{204, "", fmt.Errorf("exec: %w", syscall.ECONNRESET), ClassNetwork},
+		{503, "busy", &StatusError{StatusCode: 503}, "http 503"},
+		{400, `{"error":"partial write: field type conflict: input field \"n\" is type float"}`, &StatusError{StatusCode: 400}, ClassFieldTypeConflict},
+		{400, `{"error":"partial write: points beyond retention policy dropped=1"}`, &StatusError{StatusCode: 400}, ClassPartialWrite},
+		{0, "", &mysql.MySQLError{Number: 1213, Message: "Deadlock found"}, "mysql 1213"},
+		{0, "", &pq.Error{Code: "40P01"}, "postgres 40P01"},
+		{200, "", errors.New("query error"), ClassOther},
+	}
+	for _, tt := range tests {
+		if got := Classify(tt.status, tt.body, tt.err); got != tt.exp {
+			t.Errorf("Wrong class of %d %v. Got %v, Expected: %v\n", tt.status, tt.err, got, tt.exp)
+		}
+	}
+}
+
+func TestLineProtocolClient_Send(t *testing.T) {
+	var uri, auth, body string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/ping" {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		b, _ := ioutil.ReadAll(r.Body)
+		uri, auth, body = r.URL.RequestURI(), r.Header.Get("Authorization"), string(b)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	cli, err := NewLineProtocolClient(LineProtocolConfig{
+		Name:       "lp",
+		URL:        srv.URL,
+		WritePath:  "/api/v3/write_lp?db=Stress",
+		HealthPath: "/ping",
+		Params:     map[string]string{"precision": "nanosecond"},
+		Headers:    map[string]string{"Authorization": "Bearer xyz"},
+		Timeout:    time.Second,
+	})
+	if err != nil {
+		t.Fatalf("Create client failed: %v\n", err)
+	}
+	_, status, _, err := cli.Send(context.Background(), []byte("m,t=a n=1i 1\n"), 0)
+	if err != nil || status != http.StatusNoContent {
+		t.Errorf("Send failed. Got %d %v\n", status, err)
+	}
+	if exp := "/api/v3/write_lp?db=Stress&precision=nanosecond"; uri != exp {
+		t.Errorf("Wrong write uri. Got %v, Expected: %v\n", uri, exp)
+	}
+	if auth != "Bearer xyz" || body != "m,t=a n=1i 1\n" {
+		t.Errorf("Wrong request. Got auth %q body %q\n", auth, body)
+	}
+	if _, _, _, err := cli.Query(context.Background(), "select 1"); err == nil {
+		t.Errorf("Query should not be supported\n")
+	}
+}
@@ -1,6 +1,8 @@
 package client
 
 import (
+	"bytes"
+	"context"
 	"crypto/tls"
 	"errors"
 	"fmt"
@@ -9,6 +11,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/deltacat/dbstress/config"
 	"github.com/deltacat/dbstress/utils"
 	"github.com/valyala/fasthttp"
 )
@@ -18,10 +21,13 @@ type influxClient struct {
 	name    string
 	baseURL string
 	token   string
+	headers map[string]string // extra headers of writes
+	timeout time.Duration
 
 	// built out fields
 	httpClient *fasthttp.Client
 	writeURL   []byte
+	queryURL   []byte
 }
 
 // NewInfluxClient return new influx (db/file) client instance
@@ -39,7 +45,7 @@ func NewInfluxClient(cfg InfluxConfig, dump string) (Client, error) {
 			},
 		}
 	}
-	if err := checkHealth(cfg.URL); err != nil {
+	if err := checkHealth(cfg.URL + "/health"); err != nil {
 		return nil, err
 	}
 
@@ -52,14 +58,15 @@ func NewInfluxClient(cfg InfluxConfig, dump string) (Client, error) {
 	return newInfluxDbV1Client(cfg, httpClient), nil
 }
 
-func checkHealth(host string) error {
-	resp, err := http.Get(host + "/health")
+// checkHealth check health endpoint of host responds with 2xx
+func checkHealth(healthURL string) error {
+	resp, err := http.Get(healthURL)
 	if err != nil {
 		return errors.Unwrap(err)
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != 200 {
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
 		body, _ := ioutil.ReadAll(resp.Body)
 		return fmt.Errorf(
 			"check host health failed, status code: %d, body: %s",
@@ -70,7 +77,7 @@ func checkHealth(host string) error {
 	return nil
 }
 
-func (c *influxClient) Send(b []byte, gzip int) (latNs int64, statusCode int, body string, err error) {
+func (c *influxClient) Send(ctx context.Context, b []byte, gzip int) (latNs int64, statusCode int, body string, err error) {
 	req := fasthttp.AcquireRequest()
 	req.Header.SetContentTypeBytes([]byte("text/plain"))
 	req.Header.SetMethodBytes([]byte("POST"))
@@ -78,6 +85,9 @@ func (c *influxClient) Send(b []byte, gzip int) (latNs int64, statusCode int, bo
 	if c.token != "" {
 		req.Header.Add("Authorization", "Token "+c.token)
 	}
+	for k, v := range c.headers {
+		req.Header.Set(k, v)
+	}
 	if gzip != 0 {
 		req.Header.SetBytesKV([]byte("Content-Encoding"), []byte("gzip"))
 	}
@@ -87,20 +97,56 @@ func (c *influxClient) Send(b []byte, gzip int) (latNs int64, statusCode int, bo
 	resp := fasthttp.AcquireResponse()
 	start := time.Now()
 
-	do := fasthttp.Do
-	if c.httpClient != nil {
-		do = c.httpClient.Do
-	}
-
-	err = do(req, resp)
+	err = c.do(ctx, req, resp)
 	latNs = time.Since(start).Nanoseconds()
-	statusCode = resp.StatusCode()
+	if err == nil {
+		statusCode = resp.StatusCode()
+	}
 	if statusCode >= http.StatusBadRequest {
-		err = errors.New(http.StatusText(statusCode))
+		err = statusError(resp)
 	}
 
 	// Save the body.
-	if statusCode != http.StatusNoContent {
+	if statusCode != 0 && statusCode != http.StatusNoContent {
+		body = string(resp.Body())
+	}
+
+	fasthttp.ReleaseResponse(resp)
+	fasthttp.ReleaseRequest(req)
+
+	return
+}
+
+// doQuery send a read request, the response body is drained but only kept when failed
+func (c *influxClient) doQuery(ctx context.Context, uri []byte, contentType string, payload []byte) (latNs int64, statusCode int, body string, err error) {
+	req := fasthttp.AcquireRequest()
+	req.Header.SetRequestURIBytes(uri)
+	if c.token != "" {
+		req.Header.Add("Authorization", "Token "+c.token)
+	}
+	if payload != nil {
+		req.Header.SetMethodBytes([]byte("POST"))
+		req.Header.SetContentTypeBytes([]byte(contentType))
+		req.Header.SetContentLength(len(payload))
+		req.SetBody(payload)
+	} else {
+		req.Header.SetMethodBytes([]byte("GET"))
+	}
+
+	resp := fasthttp.AcquireResponse()
+	start := time.Now()
+
+	err = c.do(ctx, req, resp)
+	latNs = time.Since(start).Nanoseconds()
+	if err == nil {
+		statusCode = resp.StatusCode()
+	}
+	if statusCode >= http.StatusBadRequest {
+		err = statusError(resp)
+		body = string(resp.Body())
+	} else if err == nil && bytes.Contains(resp.Body(), []byte(`"error":`)) {
+		// influxql reports statement errors with status 200
+		err = errors.New("query error")
 		body = string(resp.Body())
 	}
 
@@ -110,7 +156,39 @@ func (c *influxClient) Send(b []byte, gzip int) (latNs int64, statusCode int, bo
 	return
 }
 
-func (c *influxClient) SendString(string) (latNs int64, statusCode int, body string, err error) {
+// do send request, giving up after timeout of connection or at deadline of ctx.
+// fasthttp could not abort a request when ctx is canceled, it is bounded by the timeout instead.
+func (c *influxClient) do(ctx context.Context, req *fasthttp.Request, resp *fasthttp.Response) error {
+	ctx, cancel := withTimeout(ctx, c.timeout)
+	defer cancel()
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	doDeadline := fasthttp.DoDeadline
+	if c.httpClient != nil {
+		doDeadline = c.httpClient.DoDeadline
+	}
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(config.DefaultRequestTimeout)
+	}
+	err := doDeadline(req, resp, deadline)
+	if errors.Is(err, fasthttp.ErrTimeout) {
+		return fmt.Errorf("%w: no response in %v", ErrTimeout, c.timeout)
+	}
+	return err
+}
+
+// statusError build error of a failure response, with delay asked by its Retry-After header
+func statusError(resp *fasthttp.Response) error {
+	return &StatusError{
+		StatusCode: resp.StatusCode(),
+		RetryAfter: parseRetryAfter(string(resp.Header.Peek("Retry-After")), time.Now()),
+	}
+}
+
+func (c *influxClient) SendString(context.Context, string) (latNs int64, statusCode int, body string, err error) {
 	return 0, 0, "", utils.ErrNotSupport
 }
 
@@ -1,6 +1,7 @@
 package client
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"sync"
@@ -47,7 +48,7 @@ func (c *influxFileClient) Create(command string) error {
 	return err
 }
 
-func (c *influxFileClient) Send(b []byte, _ int) (latNs int64, statusCode int, body string, err error) {
+func (c *influxFileClient) Send(_ context.Context, b []byte, _ int) (latNs int64, statusCode int, body string, err error) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
@@ -74,7 +75,11 @@ func (c *influxFileClient) Send(b []byte, _ int) (latNs int64, statusCode int, b
 	return
 }
 
-func (c *influxFileClient) SendString(string) (latNs int64, statusCode int, body string, err error) {
+func (c *influxFileClient) SendString(context.Context, string) (latNs int64, statusCode int, body string, err error) {
+	return 0, 0, "", utils.ErrNotSupport
+}
+
+func (c *influxFileClient) Query(context.Context, string) (latNs int64, statusCode int, body string, err error) {
 	return 0, 0, "", utils.ErrNotSupport
 }
 
@@ -1,6 +1,7 @@
 package client
 
 import (
+	"context"
 	"fmt"
 	"io/ioutil"
 	"net/http"
@@ -23,8 +24,10 @@ func newInfluxDbV1Client(cfg InfluxConfig, httpClient *fasthttp.Client) *influxC
 		influxClient: influxClient{
 			name:       cfg.Name,
 			baseURL:    cfg.URL,
+			timeout:    cfg.Timeout,
 			httpClient: httpClient,
 			writeURL:   []byte(writeURLFromConfigV1(cfg)),
+			queryURL:   []byte(queryURLFromConfigV1(cfg)),
 		},
 		database: cfg.V1.Database,
 		user:     cfg.V1.User,
@@ -43,6 +46,11 @@ func (c *influxClientV1) Reset() error {
 	return c.sendCmd("DROP DATABASE " + c.database)
 }
 
+// Query run an influxql query via /query
+func (c *influxClientV1) Query(ctx context.Context, query string) (latNs int64, statusCode int, body string, err error) {
+	return c.doQuery(ctx, []byte(string(c.queryURL)+"&q="+url.QueryEscape(query)), "", nil)
+}
+
 func (c *influxClientV1) sendCmd(cmd string) error {
 	vals := url.Values{}
 	vals.Set("q", cmd)
@@ -102,3 +110,20 @@ func writeURLFromConfigV1(cfg InfluxConfig) string {
 
 	return cfg.URL + "/write?" + params.Encode()
 }
+
+func queryURLFromConfigV1(cfg InfluxConfig) string {
+	params := url.Values{}
+	v1 := cfg.V1
+	params.Set("db", v1.Database)
+	if v1.User != "" {
+		params.Set("u", v1.User)
+	}
+	if v1.Pass != "" {
+		params.Set("p", v1.Pass)
+	}
+	if v1.RetentionPolicy != "" {
+		params.Set("rp", v1.RetentionPolicy)
+	}
+
+	return cfg.URL + "/query?" + params.Encode()
+}
@@ -1,6 +1,7 @@
 package client
 
 import (
+	"context"
 	"net/http"
 	"net/url"
 
@@ -58,6 +59,11 @@ func (c *influxClientV2) Reset() error {
 	return err
 }
 
+// Query run a flux query via /api/v2/query
+func (c *influxClientV2) Query(ctx context.Context, query string) (latNs int64, statusCode int, body string, err error) {
+	return c.doQuery(ctx, c.queryURL, "application/vnd.flux", []byte(query))
+}
+
 func (c *influxClientV2) sendCmd(endpoint, methods string, query queryMap, payload dataMap) (result []byte, err error) {
 	req := fasthttp.AcquireRequest()
 	req.Header.SetContentTypeBytes([]byte("application/json"))
@@ -107,8 +113,10 @@ func newInfluxDbV2Client(cfg InfluxConfig, httpClient *fasthttp.Client) *influxC
 			name:       cfg.Name,
 			baseURL:    cfg.URL,
 			token:      cfg.V2.Token,
+			timeout:    cfg.Timeout,
 			httpClient: httpClient,
 			writeURL:   []byte(writeURLFromConfigV2(cfg)),
+			queryURL:   []byte(queryURLFromConfigV2(cfg)),
 		},
 		orgID:  cfg.V2.OrgID,
 		bucket: cfg.V2.Bucket,
@@ -131,3 +139,10 @@ func writeURLFromConfigV2(cfg InfluxConfig) string {
 
 	return cfg.URL + "/api/v2/write?" + params.Encode()
 }
+
+func queryURLFromConfigV2(cfg InfluxConfig) string {
+	params := url.Values{}
+	params.Set("orgID", cfg.V2.OrgID)
+
+	return cfg.URL + "/api/v2/query?" + params.Encode()
+}
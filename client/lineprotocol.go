@@ -0,0 +1,78 @@
+package client
+
+import (
+	"context"
+	"crypto/tls"
+	"net/url"
+
+	"github.com/deltacat/dbstress/utils"
+	"github.com/valyala/fasthttp"
+)
+
+// lineProtocolClient generic client POSTing line protocol to a configured path,
+// written the same way as influxdb, queries are not supported
+type lineProtocolClient struct {
+	influxClient
+}
+
+// NewLineProtocolClient return new client writing line protocol over http to any target accepting it,
+// health path of the target is checked first if configured
+func NewLineProtocolClient(cfg LineProtocolConfig) (Client, error) {
+	writeURL, err := writeURLFromConfigLineProtocol(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.HealthPath != "" {
+		if err := checkHealth(cfg.URL + cfg.HealthPath); err != nil {
+			return nil, err
+		}
+	}
+
+	var httpClient *fasthttp.Client
+	if cfg.TLSSkipVerify {
+		httpClient = &fasthttp.Client{
+			TLSConfig: &tls.Config{
+				InsecureSkipVerify: true,
+			},
+		}
+	}
+	return &lineProtocolClient{
+		influxClient: influxClient{
+			name:       cfg.Name,
+			baseURL:    cfg.URL,
+			headers:    cfg.Headers,
+			timeout:    cfg.Timeout,
+			httpClient: httpClient,
+			writeURL:   []byte(writeURL),
+		},
+	}, nil
+}
+
+// Create do nothing, targets create tables on write
+func (c *lineProtocolClient) Create(string) error {
+	return nil
+}
+
+func (c *lineProtocolClient) Reset() error {
+	return utils.ErrNotSupport
+}
+
+func (c *lineProtocolClient) Query(context.Context, string) (latNs int64, statusCode int, body string, err error) {
+	return 0, 0, "", utils.ErrNotSupport
+}
+
+// writeURLFromConfigLineProtocol join write path to url, params are added to those of write path
+func writeURLFromConfigLineProtocol(cfg LineProtocolConfig) (string, error) {
+	u, err := url.Parse(cfg.URL + cfg.WritePath)
+	if err != nil {
+		return "", err
+	}
+	if len(cfg.Params) > 0 {
+		params := u.Query()
+		for k, v := range cfg.Params {
+			params.Set(k, v)
+		}
+		u.RawQuery = params.Encode()
+	}
+	return u.String(), nil
+}
@@ -1,6 +1,7 @@
 package client
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"time"
@@ -67,15 +68,41 @@ func (c *mysqlClient) Create(command string) error {
 	return err
 }
 
-func (c *mysqlClient) Send([]byte, int) (latNs int64, statusCode int, body string, err error) {
+func (c *mysqlClient) Send(context.Context, []byte, int) (latNs int64, statusCode int, body string, err error) {
 	return 0, 0, "", utils.ErrNotSupport
 }
 
-func (c *mysqlClient) SendString(query string) (latNs int64, statusCode int, body string, err error) {
+func (c *mysqlClient) SendString(ctx context.Context, query string) (latNs int64, statusCode int, body string, err error) {
+	ctx, cancel := withTimeout(ctx, c.cfg.Timeout)
+	defer cancel()
+
 	start := time.Now()
-	_, err = c.db.Exec(query)
+	_, err = c.db.ExecContext(ctx, query)
 	latNs = time.Since(start).Nanoseconds()
-	return latNs, 204, query, err
+	return latNs, 204, "", timeoutError(ctx, err)
+}
+
+// Query run a select query, all returned rows are drained
+func (c *mysqlClient) Query(ctx context.Context, query string) (latNs int64, statusCode int, body string, err error) {
+	ctx, cancel := withTimeout(ctx, c.cfg.Timeout)
+	defer cancel()
+
+	start := time.Now()
+	defer func() {
+		latNs = time.Since(start).Nanoseconds()
+	}()
+
+	rows, err := c.db.QueryContext(ctx, query)
+	if err != nil {
+		return 0, 0, "", timeoutError(ctx, err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+	}
+	if err = rows.Err(); err != nil {
+		return 0, 0, "", timeoutError(ctx, err)
+	}
+	return 0, 200, "", nil
 }
 
 func (c *mysqlClient) Close() error {
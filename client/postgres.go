@@ -0,0 +1,201 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/deltacat/dbstress/utils"
+	"github.com/lib/pq" // postgres driver
+	"github.com/sirupsen/logrus"
+)
+
+// maintenance database used to create or drop the target database
+const postgresMaintenanceDB = "postgres"
+
+type postgresClient struct {
+	db  *sql.DB
+	cfg PostgresConfig
+}
+
+// NewPostgresClient create new postgres client
+func NewPostgresClient(cfg PostgresConfig) (Client, error) {
+	db, err := connectPostgres(cfg, postgresMaintenanceDB)
+	if err != nil {
+		return nil, err
+	}
+	return &postgresClient{
+		db:  db,
+		cfg: cfg,
+	}, nil
+}
+
+func connectPostgres(cfg PostgresConfig, database string) (*sql.DB, error) {
+	u := url.URL{
+		Scheme: "postgres",
+		User:   url.UserPassword(cfg.User, cfg.Pass),
+		Host:   cfg.Host,
+		Path:   "/" + database,
+	}
+	sslMode := cfg.SSLMode
+	if sslMode == "" {
+		sslMode = "disable"
+	}
+	u.RawQuery = url.Values{"sslmode": []string{sslMode}}.Encode()
+
+	db, err := sql.Open("postgres", u.String())
+	if err != nil {
+		return nil, err
+	}
+	err = db.Ping()
+	if err != nil {
+		return nil, err
+	}
+	return db, nil
+}
+
+func (c *postgresClient) Create(command string) error {
+	if command == "" {
+		return utils.ErrInvalidArgs
+	}
+
+	// postgres has no "CREATE DATABASE IF NOT EXISTS"
+	var exists bool
+	if err := c.db.QueryRow("SELECT EXISTS(SELECT 1 FROM pg_database WHERE datname = $1)", c.cfg.Database).Scan(&exists); err != nil {
+		return err
+	}
+	if !exists {
+		if _, err := c.db.Exec(fmt.Sprintf("CREATE DATABASE %s", pq.QuoteIdentifier(c.cfg.Database))); err != nil {
+			return err
+		}
+	}
+
+	db, err := connectPostgres(c.cfg, c.cfg.Database)
+	if err != nil {
+		return err
+	}
+	c.db.Close()
+	c.db = db
+
+	logrus.WithField("command", command).Debug("creating postgres table")
+	_, err = db.Exec(command)
+
+	return err
+}
+
+// Send copy rows into postgres. b is a psql style COPY block,
+// the first line is the COPY statement, each following line is a tab separated row.
+func (c *postgresClient) Send(ctx context.Context, b []byte, _ int) (latNs int64, statusCode int, body string, err error) {
+	lines := bytes.Split(bytes.TrimRight(b, "\n"), []byte{'\n'})
+	if len(lines) < 2 {
+		return 0, 0, "", utils.ErrInvalidArgs
+	}
+
+	ctx, cancel := withTimeout(ctx, c.cfg.Timeout)
+	defer cancel()
+
+	start := time.Now()
+	defer func() {
+		latNs = time.Since(start).Nanoseconds()
+		err = timeoutError(ctx, err)
+	}()
+
+	tx, err := c.db.BeginTx(ctx, nil)
+	if err != nil {
+		return
+	}
+	stmt, err := tx.PrepareContext(ctx, string(lines[0]))
+	if err != nil {
+		tx.Rollback()
+		return
+	}
+	for _, line := range lines[1:] {
+		cols := bytes.Split(line, []byte{'\t'})
+		vals := make([]interface{}, len(cols))
+		for i, col := range cols {
+			vals[i] = string(col)
+		}
+		if _, err = stmt.ExecContext(ctx, vals...); err != nil {
+			stmt.Close()
+			tx.Rollback()
+			return
+		}
+	}
+	// flush buffered rows
+	if _, err = stmt.ExecContext(ctx); err != nil {
+		stmt.Close()
+		tx.Rollback()
+		return
+	}
+	if err = stmt.Close(); err != nil {
+		tx.Rollback()
+		return
+	}
+	err = tx.Commit()
+	return latNs, 204, "", err
+}
+
+func (c *postgresClient) SendString(ctx context.Context, query string) (latNs int64, statusCode int, body string, err error) {
+	ctx, cancel := withTimeout(ctx, c.cfg.Timeout)
+	defer cancel()
+
+	start := time.Now()
+	_, err = c.db.ExecContext(ctx, query)
+	latNs = time.Since(start).Nanoseconds()
+	return latNs, 204, "", timeoutError(ctx, err)
+}
+
+// Query run a select query, all returned rows are drained
+func (c *postgresClient) Query(ctx context.Context, query string) (latNs int64, statusCode int, body string, err error) {
+	ctx, cancel := withTimeout(ctx, c.cfg.Timeout)
+	defer cancel()
+
+	start := time.Now()
+	defer func() {
+		latNs = time.Since(start).Nanoseconds()
+	}()
+
+	rows, err := c.db.QueryContext(ctx, query)
+	if err != nil {
+		return 0, 0, "", timeoutError(ctx, err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+	}
+	if err = rows.Err(); err != nil {
+		return 0, 0, "", timeoutError(ctx, err)
+	}
+	return 0, 200, "", nil
+}
+
+func (c *postgresClient) Close() error {
+	if c.db != nil {
+		c.db.Close()
+		logrus.Debug("postgres client closed")
+	}
+	return nil
+}
+
+func (c *postgresClient) Reset() error {
+	// can not drop the currently open database, switch to maintenance database first
+	db, err := connectPostgres(c.cfg, postgresMaintenanceDB)
+	if err != nil {
+		return err
+	}
+	c.db.Close()
+	c.db = db
+
+	_, err = c.db.Exec(fmt.Sprintf("DROP DATABASE IF EXISTS %s;", pq.QuoteIdentifier(c.cfg.Database)))
+	return err
+}
+
+func (c *postgresClient) Name() string {
+	return c.cfg.Name
+}
+
+func (c *postgresClient) Connection() string {
+	return c.cfg.Host
+}
@@ -1,13 +1,20 @@
 package cmd
 
 import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
 	"strings"
 	"time"
 
 	"github.com/deltacat/dbstress/csv"
+	"github.com/deltacat/dbstress/report"
 	"github.com/deltacat/dbstress/runner"
+	"github.com/mitchellh/mapstructure"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 )
 
 var caseCmd = &cobra.Command{
@@ -28,7 +35,7 @@ func init() {
 }
 
 func runCases(cmd *cobra.Command, args []string) {
-	runner.Setup(cfg.Cases.Tick, cfg.Cases.Fast, quiet, kapacitorMode, cfg.Points, cfg.StatsRecord)
+	runner.Setup(cfg.Cases.Tick, cfg.Cases.Fast, quiet, kapacitorMode, cfg.Points, cfg.Query, statsRecordConnection(), cfg.Prometheus)
 	defer runner.Close()
 
 	casesToRun := []string{}
@@ -38,34 +45,124 @@ func runCases(cmd *cobra.Command, args []string) {
 		casesToRun = cfg.Cases.CasesFilter
 	}
 
-	runners := runner.BuildAllRunners(cfg, loadCases(), casesToRun)
+	cases := runner.FilterCases(loadCases(), casesToRun)
+	mustValidConfig(cases)
+	runner.SetIntervals(cfg.Cases.Interval, cfg.Cases.IntervalsDir, cfg.Cases.IntervalsFormat)
+	runner.SetLosslessStats(cfg.Cases.LosslessStats)
+
+	runners := runner.BuildAllRunners(cfg, cases, nil)
 	if len(runners) == 0 {
 		logrus.Warnln("no valid case to run")
 		return
 	}
-	defer runner.Report()
+	ctx, cancel := interruptContext()
+	defer cancel()
+	start := time.Now()
+	failed := 0
+	defer func() {
+		runner.Report(reportFormat, reportFile)
+		saveResult(start)
+		// exit as interrupted or failed once report is saved
+		switch {
+		case ctx.Err() != nil:
+			runner.Close()
+			os.Exit(130)
+		case failed > 0:
+			logrus.WithField("failed", failed).Error("some cases failed")
+			runner.Close()
+			os.Exit(1)
+		}
+	}()
 
 	logrus.WithField("cases", casesToRun).WithField("build", len(runners)).Infof("build runner from cases config, start run")
 
 	delay := cfg.Cases.Delay
 	for i, r := range runners {
 		logrus.WithFields(logrus.Fields(r.Info())).Infof("running case %d/%d", i+1, len(runners))
-		err := r.Run()
+		err := r.Run(ctx)
 		logger := logrus.WithFields(logrus.Fields(r.Result()))
 		if err != nil {
-			logger = logrus.WithError(err)
+			logger = logger.WithError(err)
+		}
+		if errors.Is(err, runner.ErrCaseFailed) {
+			failed++
+		}
+		if ctx.Err() != nil {
+			logger.WithField("skipped", len(runners)-i-1).Warn("case interrupted, skip remaining cases")
+			return
 		}
 		if i == len(runners)-1 {
 			logger.Info("finished case")
 		} else {
 			logger.WithField("wait", delay).Info("finished case, wait a while before next")
-			<-time.Tick(delay)
+			select {
+			case <-time.After(delay):
+			case <-ctx.Done():
+				logrus.WithField("skipped", len(runners)-i-1).Warn("interrupted, skip remaining cases")
+				return
+			}
 		}
 	}
 }
 
+// saveResult save structured result of the run, which could be compared later
+func saveResult(start time.Time) {
+	if cfg.Cases.ResultsDir == "" {
+		return
+	}
+	filename := report.ResultFileName(cfg.Cases.ResultsDir, start)
+	if err := report.SaveResult(filename, runner.BuildResult(version.Version, start)); err != nil {
+		logrus.WithError(err).WithField("file", filename).Error("save result failed")
+		return
+	}
+	logrus.WithField("file", filename).Info("result saved")
+}
+
+// loadCases load cases defined in config ([[cases.case]]) followed by cases in cases-file,
+// which could be csv, toml, yaml or json. Cases in toml/yaml/json file are defined as [[case]].
 func loadCases() []runner.CaseConfig {
 	cases := []runner.CaseConfig{}
-	csv.Parse("./cases.csv", &cases)
-	return cases
+	if err := viper.UnmarshalKey("cases.case", &cases, viper.DecodeHook(caseDecodeHook)); err != nil {
+		logrus.WithError(err).Fatal("parse cases in config failed")
+	}
+
+	filename := cfg.Cases.CasesFile
+	if filename == "" {
+		return cases
+	}
+	if _, err := os.Stat(filename); os.IsNotExist(err) {
+		logrus.WithField("file", filename).Debug("cases file not found")
+		return cases
+	}
+
+	fileCases := []runner.CaseConfig{}
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".csv":
+		if err := csv.Parse(filename, &fileCases); err != nil {
+			logrus.WithError(err).WithField("file", filename).Fatal("parse cases file failed")
+		}
+	default:
+		v := viper.New()
+		v.SetConfigFile(filename)
+		if err := v.ReadInConfig(); err != nil {
+			logrus.WithError(err).WithField("file", filename).Fatal("read cases file failed")
+		}
+		if err := v.UnmarshalKey("case", &fileCases, viper.DecodeHook(caseDecodeHook)); err != nil {
+			logrus.WithError(err).WithField("file", filename).Fatal("parse cases file failed")
+		}
+	}
+	return append(cases, fileCases...)
 }
+
+// caseDecodeHook decode durations of case config, besides the viper defaults
+var caseDecodeHook = mapstructure.ComposeDecodeHookFunc(
+	mapstructure.StringToTimeDurationHookFunc(),
+	mapstructure.StringToSliceHookFunc(","),
+	func(f reflect.Type, t reflect.Type, data interface{}) (interface{}, error) {
+		if t != reflect.TypeOf(csv.Duration{}) || f.Kind() != reflect.String {
+			return data, nil
+		}
+		d, err := time.ParseDuration(data.(string))
+		return csv.Duration{Duration: d}, err
+	},
+)
@@ -26,9 +26,15 @@ func init() {
 func listCases(cmd *cobra.Command, args []string) {
 	tw := tablewriter.NewWriter(os.Stdout)
 	for _, cc := range loadCases() {
+		driver := "?"
+		if ci, err := runner.ResolveConnection(cfg, cc); err == nil {
+			cc.Connection, driver = ci.Name, ci.Driver
+		}
 		tw.Append([]string{
 			cc.Name,
 			cc.Connection,
+			driver,
+			cc.Action,
 			strconv.FormatInt(int64(cc.Concurrent), 10),
 			strconv.FormatInt(int64(cc.BatchSize), 10),
 			cc.Runtime.String(),
@@ -36,7 +42,7 @@ func listCases(cmd *cobra.Command, args []string) {
 
 	}
 	if tw.NumLines() > 0 {
-		tw.SetHeader([]string{"name", "connection", "concur", "batch", "run"})
+		tw.SetHeader([]string{"name", "connection", "driver", "action", "concur", "batch", "run"})
 		tw.Render()
 	}
 }
@@ -52,6 +58,7 @@ func genCases(cmd *cobra.Command, args []string) {
 	tmpl := []runner.CaseConfig{{
 		Name:       "Sample",
 		Connection: "Influx1.8",
+		Action:     runner.ActionInsert,
 		Concurrent: 20,
 		BatchSize:  2000,
 		Runtime:    csv.Duration{Duration: time.Second * 30},
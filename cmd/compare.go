@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/deltacat/dbstress/report"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var compareCmd = &cobra.Command{
+	Use:   "compare BASE CURRENT",
+	Short: "compare two saved cases results, exit non-zero on regression",
+	Long:  "Compare result files saved by 'cases' (see cases.results-dir), print per case deltas of throughput, failure rate and latency percentiles.",
+	Args:  cobra.ExactArgs(2),
+	Run:   runCompare,
+}
+
+var (
+	regressionThreshold float64
+)
+
+func init() {
+	rootCmd.AddCommand(compareCmd)
+
+	compareCmd.Flags().Float64VarP(&regressionThreshold, "threshold", "t", 10, "Regression threshold, percent of throughput/latency change or percentage points of failure rate change")
+}
+
+func runCompare(cmd *cobra.Command, args []string) {
+	base, err := report.LoadResult(args[0])
+	if err != nil {
+		logrus.WithError(err).WithField("file", args[0]).Fatal("load base result failed")
+	}
+	current, err := report.LoadResult(args[1])
+	if err != nil {
+		logrus.WithError(err).WithField("file", args[1]).Fatal("load current result failed")
+	}
+
+	deltas := report.Compare(base, current, regressionThreshold)
+	report.RenderDeltas(os.Stdout, deltas)
+
+	if report.HasRegression(deltas) {
+		logrus.WithField("threshold", regressionThreshold).Error("regression detected")
+		os.Exit(1)
+	}
+}
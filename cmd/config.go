@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/deltacat/dbstress/config"
+	"github.com/deltacat/dbstress/runner"
+	"github.com/olekukonko/tablewriter"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "configuration tools",
+	Long:  "",
+}
+
+var configCheckCmd = &cobra.Command{
+	Use:   "check",
+	Short: "check configuration and cases, exit non-zero on problems",
+	Long: "Check connections (names, defaults, precision, consistency), points template and cases, " +
+		"then try to reach host of every connection.",
+	Run: runConfigCheck,
+}
+
+var (
+	reachTimeout time.Duration
+	skipReach    bool
+)
+
+func init() {
+	rootCmd.AddCommand(configCmd)
+	configCmd.AddCommand(configCheckCmd)
+
+	configCheckCmd.Flags().DurationVarP(&reachTimeout, "timeout", "", 3*time.Second, "Timeout of reaching each host")
+	configCheckCmd.Flags().BoolVarP(&skipReach, "skip-reach", "", false, "Do not try to reach hosts")
+}
+
+func runConfigCheck(cmd *cobra.Command, args []string) {
+	if file := viper.ConfigFileUsed(); file != "" {
+		fmt.Printf("Config file: %s\n", file)
+	}
+	cases := loadCases()
+	conns := cfg.ConnectionList()
+	fmt.Printf("Checking %d connection(s) and %d case(s)\n\n", len(conns), len(cases))
+
+	problems := append(cfg.Validate(), runner.ValidateCases(cfg, cases)...)
+	for _, err := range problems {
+		fmt.Printf("  x %v\n", err)
+	}
+	if len(problems) == 0 {
+		fmt.Println("  no problem found")
+	}
+
+	unreachable := 0
+	if !skipReach && len(conns) > 0 {
+		fmt.Println()
+		tw := tablewriter.NewWriter(os.Stdout)
+		tw.SetHeader([]string{"connection", "driver", "address", "reach"})
+		for i, err := range reachConnections(conns, reachTimeout) {
+			status := "ok"
+			if err != nil {
+				status = err.Error()
+				unreachable++
+			}
+			tw.Append([]string{conns[i].Name, conns[i].Driver, conns[i].Addr, status})
+		}
+		tw.Render()
+	}
+
+	if len(problems) > 0 || unreachable > 0 {
+		fmt.Printf("\n%d problem(s), %d unreachable connection(s)\n", len(problems), unreachable)
+		os.Exit(1)
+	}
+}
+
+// reachConnections dial every connection concurrently, return error of each
+func reachConnections(conns []config.ConnectionInfo, timeout time.Duration) []error {
+	errs := make([]error, len(conns))
+	var wg sync.WaitGroup
+	wg.Add(len(conns))
+	for i, ci := range conns {
+		go func(i int, addr string) {
+			defer wg.Done()
+			conn, err := net.DialTimeout("tcp", addr, timeout)
+			if err != nil {
+				errs[i] = err
+				return
+			}
+			conn.Close()
+		}(i, ci.Addr)
+	}
+	wg.Wait()
+	return errs
+}
+
+// mustValidConfig exit if config or cases to run have problems, unreachable hosts are left to runners
+func mustValidConfig(cases []runner.CaseConfig) {
+	problems := append(cfg.Validate(), runner.ValidateCases(cfg, cases)...)
+	for _, err := range problems {
+		logrus.Error(err)
+	}
+	if len(problems) > 0 {
+		logrus.Fatalf("%d config problem(s) found, see 'dbstress config check'", len(problems))
+	}
+}
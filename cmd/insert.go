@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"math"
 	"time"
@@ -8,6 +9,7 @@ import (
 	"github.com/deltacat/dbstress/client"
 	"github.com/deltacat/dbstress/csv"
 	"github.com/deltacat/dbstress/data/mysql"
+	"github.com/deltacat/dbstress/data/postgres"
 	"github.com/deltacat/dbstress/runner"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
@@ -16,12 +18,13 @@ import (
 var (
 	concurrency, batchSize, pointsN uint64
 
-	tick    time.Duration
-	fast    bool
-	layout  mysql.Layout
-	dump    string
-	seriesN int
-	runtime time.Duration
+	tick     time.Duration
+	fast     bool
+	openLoop bool
+	layout   mysql.Layout
+	dump     string
+	seriesN  int
+	runtime  time.Duration
 )
 
 var insertCmd = &cobra.Command{
@@ -36,6 +39,7 @@ func init() {
 
 	insertCmd.Flags().DurationVarP(&tick, "tick", "", time.Second, "Amount of time between request")
 	insertCmd.Flags().BoolVarP(&fast, "fast", "f", false, "Run as fast as possible")
+	insertCmd.Flags().BoolVarP(&openLoop, "open-loop", "", false, "Schedule writes on a fixed timeline of pps and measure latency from intended send time")
 	insertCmd.Flags().IntVarP(&seriesN, "series", "s", 100000, "number of series that will be written")
 	insertCmd.Flags().Uint64VarP(&pointsN, "points", "n", math.MaxUint64, "number of points that will be written")
 	insertCmd.Flags().Uint64VarP(&batchSize, "batch-size", "b", 10000, "number of points in a batch")
@@ -44,8 +48,9 @@ func init() {
 }
 
 func runInsert(cmd *cobra.Command, args []string) {
+	mustValidConfig(nil)
 
-	runner.Setup(tick, fast, quiet, kapacitorMode, cfg.Points, cfg.StatsRecord)
+	runner.Setup(tick, fast, quiet, kapacitorMode, cfg.Points, cfg.Query, statsRecordConnection(), cfg.Prometheus)
 	defer runner.Close()
 
 	concurrency = pps / batchSize
@@ -62,25 +67,48 @@ func runInsert(cmd *cobra.Command, args []string) {
 		if fast {
 			fmt.Println("Output is unthrottled")
 		} else {
-			fmt.Printf("Throttling output to ~%d points/sec\n", pps)
+			fmt.Printf("Throttling output to %d points/sec\n", pps)
 		}
 		fmt.Printf("Using %d concurrent writer(s)\n", concurrency)
 
 		fmt.Printf("Running until ~%d points sent or until ~%v has elapsed\n", pointsN, runtime)
 	}
 
+	ctx, cancel := interruptContext()
+	defer cancel()
+
 	logrus.Info("will insert to influxdb")
-	if err := insertInflux(); err != nil {
+	if err := insertInflux(ctx); err != nil {
 		logrus.WithError(err).Error("error with inserting influxdb")
 	}
+	if ctx.Err() != nil {
+		return
+	}
 	logrus.Info("will insert to mysql")
-	if err := insertMysql(); err != nil {
+	if err := insertMysql(ctx); err != nil {
 		logrus.WithError(err).Error("error with inserting mysql")
 	}
+	if ctx.Err() != nil {
+		return
+	}
+	if len(cfg.Connection.Postgres) > 0 {
+		logrus.Info("will insert to postgres")
+		if err := insertPostgres(ctx); err != nil {
+			logrus.WithError(err).Error("error with inserting postgres")
+		}
+	}
+
+}
 
+// targetPPS return points per second every insert case is limited to
+func targetPPS() uint64 {
+	if fast {
+		return 0
+	}
+	return pps
 }
 
-func insertMysql() error {
+func insertMysql(ctx context.Context) error {
 	cc, err := cfg.FindDefaultMySQLConnection()
 	if err != nil {
 		return err
@@ -107,13 +135,46 @@ func insertMysql() error {
 		Concurrent: int(concurrency),
 		BatchSize:  int(batchSize),
 		Runtime:    csv.Duration{Duration: runtime},
+		PPS:        targetPPS(),
+		OpenLoop:   openLoop && !fast,
 	}
 
 	r := runner.NewMySQLRunner(cli, cs, layout)
-	return r.Run()
+	return r.Run(ctx)
+}
+
+func insertPostgres(ctx context.Context) error {
+	cc, err := cfg.FindDefaultPostgresConnection()
+	if err != nil {
+		return err
+	}
+
+	cli, err := client.NewPostgresClient(cc)
+	if err != nil {
+		return err
+	}
+	defer cli.Close()
+
+	pgLayout, err := postgres.GenerateLayout(measurement, seriesKey, fieldStr, cc.Hypertable)
+	if err != nil {
+		return err
+	}
+
+	cs := runner.CaseConfig{
+		Name:       "Insert Postgres",
+		Connection: cc.Name,
+		Concurrent: int(concurrency),
+		BatchSize:  int(batchSize),
+		Runtime:    csv.Duration{Duration: runtime},
+		PPS:        targetPPS(),
+		OpenLoop:   openLoop && !fast,
+	}
+
+	r := runner.NewPostgresRunner(cli, cs, pgLayout, cc.CopyFrom)
+	return r.Run(ctx)
 }
 
-func insertInflux() error {
+func insertInflux(ctx context.Context) error {
 	cc, err := cfg.FindDefaultInfluxDBConnection()
 	if err != nil {
 		return err
@@ -133,8 +194,10 @@ func insertInflux() error {
 		Concurrent: int(concurrency),
 		BatchSize:  int(batchSize),
 		Runtime:    csv.Duration{Duration: runtime},
+		PPS:        targetPPS(),
+		OpenLoop:   openLoop && !fast,
 	}
 	r := runner.NewInfluxRunner(cli, cs)
 
-	return r.Run()
+	return r.Run(ctx)
 }
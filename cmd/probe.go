@@ -0,0 +1,173 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/deltacat/dbstress/config"
+	"github.com/deltacat/dbstress/csv"
+	"github.com/deltacat/dbstress/report"
+	"github.com/deltacat/dbstress/runner"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// probe modes
+const (
+	probeModeRate        = "rate"
+	probeModeConcurrency = "concurrency"
+)
+
+var probeCmd = &cobra.Command{
+	Use:   "probe",
+	Short: "search max sustainable throughput of a connection",
+	Long: "Run short cases with increasing rate (or concurrency) against a connection, " +
+		"stop when p99 latency or error rate crosses the threshold and report the highest sustainable throughput.",
+	Run: runProbe,
+}
+
+var (
+	probeConnection  string
+	probeMode        string
+	probeStart       uint64
+	probeStep        uint64
+	probeMax         uint64
+	probeConcurrent  int
+	probeBatchSize   int
+	probeStepRuntime time.Duration
+	probeDelay       time.Duration
+	probeMaxP99      time.Duration
+	probeMaxErrRate  float64
+	probeMinAchieved float64
+)
+
+func init() {
+	rootCmd.AddCommand(probeCmd)
+
+	probeCmd.Flags().StringVarP(&probeConnection, "connection", "c", "", "Connection to probe. Default the default influxdb connection")
+	probeCmd.Flags().StringVarP(&probeMode, "mode", "m", probeModeRate, "Increase rate (points per second) or concurrency each step")
+	probeCmd.Flags().Uint64VarP(&probeStart, "start", "", 10000, "Rate or concurrency of the first step")
+	probeCmd.Flags().Uint64VarP(&probeStep, "step", "", 10000, "Rate or concurrency added each step")
+	probeCmd.Flags().Uint64VarP(&probeMax, "max", "", 1000000, "Stop after the step reaching this rate or concurrency")
+	probeCmd.Flags().IntVarP(&probeConcurrent, "concurrent", "", 10, "Concurrent writers in rate mode")
+	probeCmd.Flags().IntVarP(&probeBatchSize, "batch-size", "b", 1000, "number of points in a batch")
+	probeCmd.Flags().DurationVarP(&probeStepRuntime, "step-runtime", "", 10*time.Second, "Runtime of each step")
+	probeCmd.Flags().DurationVarP(&probeDelay, "delay", "", 5*time.Second, "Wait between steps")
+	probeCmd.Flags().DurationVarP(&probeMaxP99, "max-p99", "", time.Second, "Step fails if p99 latency is above")
+	probeCmd.Flags().Float64VarP(&probeMaxErrRate, "max-error-rate", "", 1, "Step fails if percent of failed points is above")
+	probeCmd.Flags().Float64VarP(&probeMinAchieved, "min-achieved", "", 90, "In rate mode, step fails if achieved rate is below this percent of target")
+}
+
+func runProbe(cmd *cobra.Command, args []string) {
+	if probeMode != probeModeRate && probeMode != probeModeConcurrency {
+		logrus.Fatalf("expect probe mode rate or concurrency, got '%s'", probeMode)
+	}
+	if probeStart == 0 || probeStep == 0 || probeBatchSize <= 0 || probeConcurrent <= 0 {
+		logrus.Fatal("start, step, batch-size and concurrent must be positive")
+	}
+	if probeStepRuntime < time.Second {
+		logrus.Fatal("step-runtime must be at least 1s")
+	}
+	mustValidConfig(nil)
+	conn, err := probeTarget()
+	if err != nil {
+		logrus.WithError(err).WithField("connection", probeConnection).Fatal("connection not found")
+	}
+
+	// steps are throttled by the rate limiter in rate mode, and run as fast as possible in concurrency mode
+	runner.Setup(cfg.Cases.Tick, true, quiet, kapacitorMode, cfg.Points, cfg.Query, statsRecordConnection(), cfg.Prometheus)
+	defer runner.Close()
+
+	ctx, cancel := interruptContext()
+	defer cancel()
+
+	var best *report.Record
+	for i, n := 0, probeStart; n <= probeMax; i, n = i+1, n+probeStep {
+		cs := runner.CaseConfig{
+			Name:       fmt.Sprintf("probe-%s-%d", conn.Name, i+1),
+			Connection: conn.Name,
+			Concurrent: probeConcurrent,
+			BatchSize:  probeBatchSize,
+			Runtime:    csv.Duration{Duration: probeStepRuntime},
+		}
+		if probeMode == probeModeRate {
+			cs.PPS = n
+		} else {
+			cs.Concurrent = int(n)
+		}
+
+		r, err := runner.BuildRunner(cfg, cs)
+		if err != nil {
+			logrus.WithError(err).Fatal("create probe runner failed")
+		}
+		logrus.WithFields(logrus.Fields(r.Info())).WithField(probeMode, n).Infof("running probe step %d", i+1)
+		if err := r.Run(ctx); err != nil {
+			logrus.WithError(err).Error("probe step failed")
+			break
+		}
+		if ctx.Err() != nil {
+			logrus.Warn("probe interrupted")
+			break
+		}
+		recs := r.Records()
+		if len(recs) == 0 {
+			break
+		}
+		rec := recs[0]
+		if reason := probeExceeded(rec); reason != "" {
+			logrus.WithFields(logrus.Fields(r.Result())).WithField("reason", reason).Info("probe step not sustainable, stop")
+			break
+		}
+		logrus.WithFields(logrus.Fields(r.Result())).Info("probe step sustainable")
+		if best == nil || rec.Throughput > best.Throughput {
+			best = &rec
+		}
+		if n+probeStep <= probeMax {
+			select {
+			case <-time.After(probeDelay):
+			case <-ctx.Done():
+			}
+		}
+	}
+
+	runner.Report(reportFormat, reportFile)
+	if best == nil {
+		logrus.Warn("no sustainable step found, try a lower start")
+		return
+	}
+	logrus.WithFields(logrus.Fields{
+		"case":       best.Case,
+		"concurrent": best.Concurrent,
+		"target":     best.TargetPPS,
+		"p99":        fmtProbeMs(best.LatP99Ms),
+	}).Infof("max sustainable throughput: %d points/sec", best.Throughput)
+}
+
+// probeTarget return connection to probe, the default influxdb connection if not given
+func probeTarget() (config.ConnectionInfo, error) {
+	if probeConnection == "" {
+		return cfg.FindDefaultConnection(config.DriverInfluxDB)
+	}
+	return cfg.FindConnection(probeConnection)
+}
+
+// probeExceeded return why a step is not sustainable, empty if it is
+func probeExceeded(rec report.Record) string {
+	if rec.Points == 0 {
+		return "no point written"
+	}
+	if errRate := float64(rec.Failed) / float64(rec.Points) * 100; errRate > probeMaxErrRate {
+		return fmt.Sprintf("error rate %.2f%% above %.2f%%", errRate, probeMaxErrRate)
+	}
+	if p99 := time.Duration(rec.LatP99Ms * float64(time.Millisecond)); p99 > probeMaxP99 {
+		return fmt.Sprintf("p99 latency %v above %v", p99, probeMaxP99)
+	}
+	if rec.TargetPPS > 0 && rec.AchievedPPS < float64(rec.TargetPPS)*probeMinAchieved/100 {
+		return fmt.Sprintf("achieved %.0f points/sec below %.0f%% of target %d", rec.AchievedPPS, probeMinAchieved, rec.TargetPPS)
+	}
+	return ""
+}
+
+func fmtProbeMs(ms float64) time.Duration {
+	return time.Duration(ms * float64(time.Millisecond)).Round(10 * time.Microsecond)
+}
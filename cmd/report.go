@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/deltacat/dbstress/report"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var reportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "render saved cases results",
+	Long:  "",
+}
+
+var reportHTMLCmd = &cobra.Command{
+	Use:   "html [RESULT]",
+	Short: "render saved cases result as html page with charts",
+	Long: "Render result file saved by 'cases' (the latest one in cases.results-dir if not given) as a self-contained html page, " +
+		"with throughput over time and latency charts of every case and comparison across connections.",
+	Args: cobra.MaximumNArgs(1),
+	Run:  runReportHTML,
+}
+
+var (
+	htmlOutput string
+)
+
+func init() {
+	rootCmd.AddCommand(reportCmd)
+	reportCmd.AddCommand(reportHTMLCmd)
+
+	reportHTMLCmd.Flags().StringVarP(&htmlOutput, "output", "o", "", "Output file. Default result file name with .html extension")
+}
+
+func runReportHTML(cmd *cobra.Command, args []string) {
+	var resultFile string
+	if len(args) > 0 {
+		resultFile = args[0]
+	} else {
+		latest, err := report.LatestResultFile(cfg.Cases.ResultsDir)
+		if err != nil {
+			logrus.WithError(err).Fatal("find latest result failed")
+		}
+		resultFile = latest
+	}
+
+	res, err := report.LoadResult(resultFile)
+	if err != nil {
+		logrus.WithError(err).WithField("file", resultFile).Fatal("load result failed")
+	}
+	intervals, errs := report.LoadResultIntervals(res, resultFile)
+	for _, err := range errs {
+		logrus.WithError(err).Warn("load interval results failed, over time charts of the case are skipped")
+	}
+
+	output := htmlOutput
+	if output == "" {
+		output = strings.TrimSuffix(resultFile, filepath.Ext(resultFile)) + ".html"
+	}
+	f, err := os.Create(output)
+	if err != nil {
+		logrus.WithError(err).WithField("file", output).Fatal("create html report failed")
+	}
+	if err := report.RenderHTML(f, res, intervals); err != nil {
+		f.Close()
+		logrus.WithError(err).WithField("file", output).Fatal("render html report failed")
+	}
+	if err := f.Close(); err != nil {
+		logrus.WithError(err).WithField("file", output).Fatal("write html report failed")
+	}
+	logrus.WithField("file", output).Info("html report saved")
+}
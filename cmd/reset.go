@@ -40,6 +40,15 @@ func runReset(cmd *cobra.Command, args []string) {
 			logger.WithError(err).Error("mysql reset failed")
 		}
 	}
+	// reset all postgres
+	for _, cc := range config.Cfg.Connection.Postgres {
+		logger := logrus.WithField("connection", cc.Name)
+		if err := resetPostgres(cc); err == nil {
+			logger.Info("postgres reseted")
+		} else {
+			logger.WithError(err).Error("postgres reset failed")
+		}
+	}
 }
 
 func resetInflux(cc config.InfluxClientConfig) error {
@@ -57,3 +66,12 @@ func resetMySQL(cc config.MySQLClientConfig) error {
 	}
 	return c.Reset()
 }
+
+func resetPostgres(cc config.PostgresClientConfig) error {
+	c, err := client.NewPostgresClient(cc)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+	return c.Reset()
+}
@@ -6,14 +6,17 @@ import (
 	"strings"
 
 	"github.com/deltacat/dbstress/config"
+	"github.com/deltacat/dbstress/report"
+	"github.com/deltacat/dbstress/runner"
+	"github.com/deltacat/dbstress/utils"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 )
 
 var rootCmd = &cobra.Command{
 	Use:              "dbstress",
-	Short:            "Create artificial load on an InfluxDB/MySQL instance",
-	Long:             "This application create stress test on influxdb or mysql.\nPlease rename dbstress.sample.toml to dbstress.toml then make necessary change",
+	Short:            "Create artificial load on an InfluxDB/MySQL/PostgreSQL instance",
+	Long:             "This application create stress test on influxdb, mysql or postgresql.\nPlease rename dbstress.sample.toml to dbstress.toml then make necessary change",
 	PersistentPreRun: runRootPersistentPre,
 }
 
@@ -23,6 +26,9 @@ var (
 	quiet                 bool
 	strict, kapacitorMode bool
 	tlsSkipVerify         bool
+	reportFormat          string
+	reportFile            string
+	progressMode          string
 
 	measurement, seriesKey, fieldStr string
 )
@@ -43,7 +49,10 @@ func init() {
 	rootCmd.PersistentFlags().Uint64VarP(&pps, "pps", "", 200000, "Points Per Second")
 	rootCmd.PersistentFlags().BoolVarP(&quiet, "quiet", "q", false, "Only print the write throughput")
 	rootCmd.PersistentFlags().BoolVarP(&kapacitorMode, "kapacitor", "k", false, "Use Kapacitor mode, namely do not try to run any queries.")
-	rootCmd.PersistentFlags().BoolVarP(&strict, "strict", "", false, "Strict mode will exit as soon as an error or unexpected status is encountered")
+	rootCmd.PersistentFlags().BoolVarP(&strict, "strict", "", false, "Strict mode fails a case as soon as an error or unexpected status is encountered")
+	rootCmd.PersistentFlags().StringVarP(&reportFormat, "report-format", "", "table", "Report format: table, json, csv or markdown")
+	rootCmd.PersistentFlags().StringVarP(&progressMode, "progress", "", runner.ProgressAuto, "Progress view of running case: auto, tty, plain or off")
+	rootCmd.PersistentFlags().StringVarP(&reportFile, "report-file", "", "", "Also save report to file, in format of its extension (.json, .csv, .md or .txt) if recognised, otherwise --report-format")
 
 	loggerFormatter := new(logrus.TextFormatter)
 	loggerFormatter.TimestampFormat = "2006-01-02 15:04:05"
@@ -58,9 +67,16 @@ func runRootPersistentPre(cmd *cobra.Command, args []string) {
 	seriesKey = cfg.Points.SeriesKey
 	fieldStr = cfg.Points.FieldsStr
 
-	if !strings.Contains(seriesKey, ",") && !strings.Contains(seriesKey, "=") {
-		logrus.Warnf("expect series like 'ctr,some=tag', got '%s'", seriesKey)
+	if !report.IsValidFormat(reportFormat) {
+		logrus.Warnf("expect report format table, json, csv or markdown, got '%s'", reportFormat)
 		os.Exit(1)
 		return
 	}
+	if !utils.ArrayContainsStringIgnoreCase(runner.ProgressModes, progressMode) {
+		logrus.Warnf("expect progress auto, tty, plain or off, got '%s'", progressMode)
+		os.Exit(1)
+		return
+	}
+	runner.SetProgress(strings.ToLower(progressMode))
+	runner.SetStrict(strict)
 }
@@ -33,6 +33,7 @@ func initConfig() {
 	if err := viper.Unmarshal(&config.Cfg); err != nil {
 		logrus.WithError(err).Fatal("unmarshal config error")
 	}
+	config.Cfg.FillConnectionDefaults()
 }
 
 func viperBindEnvs(iface interface{}, parts ...string) {
@@ -67,27 +68,36 @@ func setDefaultConfig() {
 	viper.SetDefault("stats-record.database", "stress_stats")
 	viper.SetDefault("stats-record.enable", false)
 
-	viper.SetDefault("connection.influxdb.url", "http://127.0.0.1:8086")
-	viper.SetDefault("connection.influxdb.user", "")
-	viper.SetDefault("connection.influxdb.pass", "")
-	viper.SetDefault("connection.influxdb.db", "stress")
-	viper.SetDefault("connection.influxdb.rp", "")
-	viper.SetDefault("connection.influxdb.precision", "n")
-	viper.SetDefault("connection.influxdb.consistency", "one")
-	viper.SetDefault("connection.influxdb.tls-skip-verify", false)
-	viper.SetDefault("connection.influxdb.gzip", -1)
-
-	viper.SetDefault("connection.mysql.url", "127.0.0.1:3308")
-	viper.SetDefault("connection.mysql.user", "")
-	viper.SetDefault("connection.mysql.pass", "")
-	viper.SetDefault("connection.mysql.db", "stress")
+	viper.SetDefault("prometheus.enable", false)
+	viper.SetDefault("prometheus.listen", ":9273")
 
 	viper.SetDefault("points.measurement", "ctr")
 	viper.SetDefault("points.series-key", "some=tag")
 	viper.SetDefault("points.fields-str", "n=0i")
 	viper.SetDefault("points.series-num", 100000)
 
+	viper.SetDefault("query.templates", []string{"last", "range", "group-by"})
+	viper.SetDefault("query.window", 5*time.Minute)
+	viper.SetDefault("query.interval", time.Minute)
+
 	viper.SetDefault("cases.delay", time.Minute)
 	viper.SetDefault("cases.fast", true)
 	viper.SetDefault("cases.tick", time.Second)
+	viper.SetDefault("cases.cases-file", "cases.csv")
+	viper.SetDefault("cases.results-dir", "results")
+	viper.SetDefault("cases.interval", time.Second)
+	viper.SetDefault("cases.intervals-dir", "results/intervals")
+	viper.SetDefault("cases.intervals-format", "csv")
+}
+
+// statsRecordConnection return connection recording runtime stats, nil if not enabled
+func statsRecordConnection() *config.InfluxClientConfig {
+	if !cfg.StatsRecord.Enable {
+		return nil
+	}
+	cc, err := cfg.StatsRecordConnection()
+	if err != nil {
+		logrus.WithError(err).Fatal("stats-record connection error")
+	}
+	return &cc
 }
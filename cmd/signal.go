@@ -0,0 +1,31 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/sirupsen/logrus"
+)
+
+// interruptContext return a context canceled on first SIGINT/SIGTERM, so running case stops
+// and report is still printed. The process exits at once on the second signal.
+func interruptContext() (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(context.Background())
+	sigs := make(chan os.Signal, 2)
+	signal.Notify(sigs, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		select {
+		case sig := <-sigs:
+			logrus.WithField("signal", sig).Warn("interrupted, stopping running case, interrupt again to exit at once")
+			cancel()
+		case <-ctx.Done():
+			signal.Stop(sigs)
+			return
+		}
+		<-sigs
+		os.Exit(130)
+	}()
+	return ctx, cancel
+}
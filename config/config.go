@@ -11,30 +11,42 @@ var Cfg Config
 // Config config struct define
 type Config struct {
 	StatsRecord StatsRecordConfig `mapstructure:"stats-record"`
+	Prometheus  PrometheusConfig  `mapstructure:"prometheus"`
 	Connection  struct {
-		InfluxDB []InfluxClientConfig `mapstructure:"influxdb"`
-		MySQL    []MySQLClientConfig  `mapstructure:"mysql"`
+		InfluxDB     []InfluxClientConfig       `mapstructure:"influxdb"`
+		LineProtocol []LineProtocolClientConfig `mapstructure:"lineprotocol"`
+		MySQL        []MySQLClientConfig        `mapstructure:"mysql"`
+		Postgres     []PostgresClientConfig     `mapstructure:"postgres"`
 	} `mapstructure:"connection"`
 	Points PointsConfig `mapstructure:"points"`
+	Query  QueryConfig  `mapstructure:"query"`
 	Cases  CasesConfig  `mapstructure:"cases"`
 }
 
 // StatsRecordConfig stats record config
 type StatsRecordConfig struct {
-	Enable   bool   `mapstructure:"enable"`   // Record runtime statistics
-	Host     string `mapstructure:"host"`     // Address of InfluxDB instance where runtime statistics will be recorded
-	Database string `mapstructure:"database"` // Database that statistics will be written to
+	Enable     bool   `mapstructure:"enable"`     // Record runtime statistics
+	Connection string `mapstructure:"connection"` // Name of influxdb connection (v1 or v2) where statistics are recorded, host and database are used if not set
+	Host       string `mapstructure:"host"`       // Address of InfluxDB instance where runtime statistics will be recorded
+	Database   string `mapstructure:"database"`   // Database that statistics will be written to
+}
+
+// PrometheusConfig prometheus metrics endpoint config
+type PrometheusConfig struct {
+	Enable bool   `mapstructure:"enable"` // Serve metrics of running cases at /metrics
+	Listen string `mapstructure:"listen"` // Address to listen, e.g. ":9273"
 }
 
 // InfluxClientConfig the influxdb client config struct
 type InfluxClientConfig struct {
-	Name          string `mapstructure:"name"`
-	Default       bool   `mapstructure:"default"`
-	URL           string `mapstructure:"url"`
-	Precision     string `mapstructure:"precision"`
-	Consistency   string `mapstructure:"consistency"`
-	TLSSkipVerify bool   `mapstructure:"tls-skip-verify"`
-	APIVersion    int    `mapstructure:"api-version"`
+	Name          string        `mapstructure:"name"`
+	Default       bool          `mapstructure:"default"`
+	URL           string        `mapstructure:"url"`
+	Precision     string        `mapstructure:"precision"`
+	Consistency   string        `mapstructure:"consistency"`
+	TLSSkipVerify bool          `mapstructure:"tls-skip-verify"`
+	APIVersion    int           `mapstructure:"api-version"`
+	Timeout       time.Duration `mapstructure:"timeout"` // Per request timeout, default 30s
 	V1            struct {
 		Database        string `mapstructure:"db"`
 		RetentionPolicy string `mapstructure:"rp"`
@@ -48,14 +60,44 @@ type InfluxClientConfig struct {
 	} `mapstructure:"v2"`
 }
 
+// LineProtocolClientConfig generic line protocol over http client config, for targets accepting
+// influx line protocol like InfluxDB 3, VictoriaMetrics, QuestDB, GreptimeDB or telegraf http_listener
+type LineProtocolClientConfig struct {
+	Name          string            `mapstructure:"name"`
+	Default       bool              `mapstructure:"default"`
+	URL           string            `mapstructure:"url"`
+	WritePath     string            `mapstructure:"write-path"`  // Path batches are POSTed to, may carry query params, default /write
+	HealthPath    string            `mapstructure:"health-path"` // Path checked by GET before running, not checked if empty
+	Params        map[string]string `mapstructure:"params"`      // Query params of writes, names are lower cased by config loading
+	Headers       map[string]string `mapstructure:"headers"`     // Headers of writes, e.g. Authorization
+	Precision     string            `mapstructure:"precision"`   // Precision of timestamps generated, the target is told via params if needed
+	TLSSkipVerify bool              `mapstructure:"tls-skip-verify"`
+	Timeout       time.Duration     `mapstructure:"timeout"` // Per request timeout, default 30s
+}
+
 // MySQLClientConfig mysql client config
 type MySQLClientConfig struct {
-	Name     string `mapstructure:"name"`
-	Default  bool   `mapstructure:"default"`
-	Host     string `mapstructure:"host"`
-	User     string `mapstructure:"user"`
-	Pass     string `mapstructure:"pass"`
-	Database string `mapstructure:"db"`
+	Name     string        `mapstructure:"name"`
+	Default  bool          `mapstructure:"default"`
+	Host     string        `mapstructure:"host"`
+	User     string        `mapstructure:"user"`
+	Pass     string        `mapstructure:"pass"`
+	Database string        `mapstructure:"db"`
+	Timeout  time.Duration `mapstructure:"timeout"` // Per request timeout, default 30s
+}
+
+// PostgresClientConfig postgres client config
+type PostgresClientConfig struct {
+	Name       string        `mapstructure:"name"`
+	Default    bool          `mapstructure:"default"`
+	Host       string        `mapstructure:"host"`
+	User       string        `mapstructure:"user"`
+	Pass       string        `mapstructure:"pass"`
+	Database   string        `mapstructure:"db"`
+	SSLMode    string        `mapstructure:"sslmode"`
+	CopyFrom   bool          `mapstructure:"copy-from"`  // Write batches via COPY FROM STDIN instead of multi-row INSERT
+	Hypertable bool          `mapstructure:"hypertable"` // Create table as TimescaleDB hypertable
+	Timeout    time.Duration `mapstructure:"timeout"`    // Per request timeout, default 30s
 }
 
 // PointsConfig points to write config
@@ -67,6 +109,13 @@ type PointsConfig struct {
 	PointsN     uint64 `mapstructure:"points-num"`
 }
 
+// QueryConfig query workload config
+type QueryConfig struct {
+	Templates []string      `mapstructure:"templates"` // Builtin template (last, range, group-by) or custom query text
+	Window    time.Duration `mapstructure:"window"`    // Time range of aggregate queries
+	Interval  time.Duration `mapstructure:"interval"`  // Group by time interval of range aggregate queries
+}
+
 // CasesConfig cases config
 type CasesConfig struct {
 	Delay       time.Duration `mapstructure:"delay"`
@@ -74,4 +123,11 @@ type CasesConfig struct {
 	Tick        time.Duration `mapstructure:"tick"`
 	CasesFile   string        `mapstructure:"cases-file"`
 	CasesFilter []string      `mapstructure:"cases-filter"`
+	ResultsDir  string        `mapstructure:"results-dir"` // Directory where structured result of every run is saved
+
+	Interval        time.Duration `mapstructure:"interval"`         // Width of per-interval results buckets, 0 to disable
+	IntervalsDir    string        `mapstructure:"intervals-dir"`    // Directory where per-interval results of every case are saved
+	IntervalsFormat string        `mapstructure:"intervals-format"` // csv or json
+
+	LosslessStats bool `mapstructure:"lossless-stats"` // Record latency in workers instead of by sink, so no result is missed under heavy load
 }
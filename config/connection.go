@@ -0,0 +1,169 @@
+package config
+
+import (
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/deltacat/dbstress/utils"
+)
+
+// DefaultRequestTimeout timeout of a request if timeout of connection is not set
+const DefaultRequestTimeout = 30 * time.Second
+
+// driver types of connections
+const (
+	DriverInfluxDB     = "influxdb"
+	DriverLineProtocol = "lineprotocol"
+	DriverMySQL        = "mysql"
+	DriverPostgres     = "postgres"
+)
+
+// ConnectionInfo name and driver type of a configured connection
+type ConnectionInfo struct {
+	Name    string
+	Driver  string
+	Default bool
+	Addr    string // host:port
+}
+
+// ConnectionList return all configured connections in config order, influxdb first, then lineprotocol, mysql and postgres
+func (c *Config) ConnectionList() []ConnectionInfo {
+	conns := []ConnectionInfo{}
+	for _, cc := range c.Connection.InfluxDB {
+		conns = append(conns, ConnectionInfo{Name: cc.Name, Driver: DriverInfluxDB, Default: cc.Default, Addr: urlAddr(cc.URL)})
+	}
+	for _, cc := range c.Connection.LineProtocol {
+		conns = append(conns, ConnectionInfo{Name: cc.Name, Driver: DriverLineProtocol, Default: cc.Default, Addr: urlAddr(cc.URL)})
+	}
+	for _, cc := range c.Connection.MySQL {
+		conns = append(conns, ConnectionInfo{Name: cc.Name, Driver: DriverMySQL, Default: cc.Default, Addr: cc.Host})
+	}
+	for _, cc := range c.Connection.Postgres {
+		conns = append(conns, ConnectionInfo{Name: cc.Name, Driver: DriverPostgres, Default: cc.Default, Addr: cc.Host})
+	}
+	return conns
+}
+
+// Connections return registry of configured connections keyed by lower cased name,
+// the first one wins if names are duplicated
+func (c *Config) Connections() map[string]ConnectionInfo {
+	reg := map[string]ConnectionInfo{}
+	for _, ci := range c.ConnectionList() {
+		key := strings.ToLower(ci.Name)
+		if _, ok := reg[key]; !ok {
+			reg[key] = ci
+		}
+	}
+	return reg
+}
+
+// FindConnection find connection of any driver by name
+func (c *Config) FindConnection(name string) (ConnectionInfo, error) {
+	if ci, ok := c.Connections()[strings.ToLower(name)]; ok {
+		return ci, nil
+	}
+	return ConnectionInfo{}, utils.ErrNotFound
+}
+
+// FindDefaultConnection find default connection of driver, the first one if none is marked default
+func (c *Config) FindDefaultConnection(driver string) (ConnectionInfo, error) {
+	var first *ConnectionInfo
+	for _, ci := range c.ConnectionList() {
+		if ci.Driver != driver {
+			continue
+		}
+		if ci.Default {
+			return ci, nil
+		}
+		if first == nil {
+			ci := ci
+			first = &ci
+		}
+	}
+	if first != nil {
+		return *first, nil
+	}
+	return ConnectionInfo{}, utils.ErrNotFound
+}
+
+// StatsRecordConnection return influxdb connection where runtime statistics are recorded,
+// the named connection of [stats-record] if set, otherwise an unauthenticated v1 connection of its host and database
+func (c *Config) StatsRecordConnection() (InfluxClientConfig, error) {
+	sr := c.StatsRecord
+	if sr.Connection != "" {
+		return c.FindInfluxDBConnection(sr.Connection)
+	}
+	cc := InfluxClientConfig{
+		Name:        "stats-record",
+		URL:         sr.Host,
+		APIVersion:  1,
+		Precision:   "n",
+		Consistency: "any",
+		Timeout:     DefaultRequestTimeout,
+	}
+	cc.V1.Database = sr.Database
+	cc.V1.RetentionPolicy = "autogen"
+	return cc, nil
+}
+
+// FillConnectionDefaults fill fields not set of every configured connection with defaults
+func (c *Config) FillConnectionDefaults() {
+	for i := range c.Connection.InfluxDB {
+		cc := &c.Connection.InfluxDB[i]
+		setDefault(&cc.URL, "http://127.0.0.1:8086")
+		setDefault(&cc.Precision, "n")
+		setDefault(&cc.Consistency, "one")
+		setDefault(&cc.V1.Database, "stress")
+		setDefault(&cc.V2.Bucket, "stress")
+		setDefaultDuration(&cc.Timeout, DefaultRequestTimeout)
+	}
+	for i := range c.Connection.LineProtocol {
+		cc := &c.Connection.LineProtocol[i]
+		setDefault(&cc.URL, "http://127.0.0.1:8086")
+		setDefault(&cc.WritePath, "/write")
+		setDefault(&cc.Precision, "n")
+		setDefaultDuration(&cc.Timeout, DefaultRequestTimeout)
+	}
+	for i := range c.Connection.MySQL {
+		cc := &c.Connection.MySQL[i]
+		setDefault(&cc.Host, "127.0.0.1:3308")
+		setDefault(&cc.Database, "stress")
+		setDefaultDuration(&cc.Timeout, DefaultRequestTimeout)
+	}
+	for i := range c.Connection.Postgres {
+		cc := &c.Connection.Postgres[i]
+		setDefault(&cc.Host, "127.0.0.1:5432")
+		setDefault(&cc.Database, "stress")
+		setDefault(&cc.SSLMode, "disable")
+		setDefaultDuration(&cc.Timeout, DefaultRequestTimeout)
+	}
+}
+
+func setDefault(v *string, def string) {
+	if *v == "" {
+		*v = def
+	}
+}
+
+func setDefaultDuration(v *time.Duration, def time.Duration) {
+	if *v == 0 {
+		*v = def
+	}
+}
+
+// urlAddr return host:port of url, port is guessed from scheme if not given
+func urlAddr(rawurl string) string {
+	u, err := url.Parse(rawurl)
+	if err != nil || u.Host == "" {
+		return rawurl
+	}
+	if u.Port() != "" {
+		return u.Host
+	}
+	if u.Scheme == "https" {
+		return net.JoinHostPort(u.Hostname(), "443")
+	}
+	return net.JoinHostPort(u.Hostname(), "80")
+}
@@ -0,0 +1,57 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/deltacat/dbstress/utils"
+)
+
+func TestConfig_FindConnection(t *testing.T) {
+	var c Config
+	c.Connection.InfluxDB = []InfluxClientConfig{{Name: "Influx1"}, {Name: "Influx2", Default: true}}
+	c.Connection.MySQL = []MySQLClientConfig{{Name: "MySQL8"}}
+	c.Connection.LineProtocol = []LineProtocolClientConfig{{Name: "VM", URL: "http://vm:8428"}}
+
+	ci, err := c.FindConnection("mysql8")
+	if err != nil || ci.Name != "MySQL8" || ci.Driver != DriverMySQL {
+		t.Errorf("Wrong connection. Got %+v, %v", ci, err)
+	}
+	if _, err := c.FindConnection("nope"); err != utils.ErrNotFound {
+		t.Errorf("Expected not found, got %v", err)
+	}
+
+	if ci, err := c.FindConnection("vm"); err != nil || ci.Driver != DriverLineProtocol || ci.Addr != "vm:8428" {
+		t.Errorf("Wrong lineprotocol connection. Got %+v, %v", ci, err)
+	}
+
+	if ci, err := c.FindDefaultConnection(DriverInfluxDB); err != nil || ci.Name != "Influx2" {
+		t.Errorf("Wrong default influxdb connection. Got %+v, %v", ci, err)
+	}
+	if ci, err := c.FindDefaultConnection(DriverMySQL); err != nil || ci.Name != "MySQL8" {
+		t.Errorf("Wrong default mysql connection. Got %+v, %v", ci, err)
+	}
+	if _, err := c.FindDefaultConnection(DriverPostgres); err != utils.ErrNotFound {
+		t.Errorf("Expected no postgres connection, got %v", err)
+	}
+}
+
+func TestConfig_StatsRecordConnection(t *testing.T) {
+	var c Config
+	c.Connection.InfluxDB = []InfluxClientConfig{{Name: "Stats", APIVersion: 2}}
+	c.StatsRecord.Host = "http://stats:8086"
+	c.StatsRecord.Database = "stress_stats"
+
+	cc, err := c.StatsRecordConnection()
+	if err != nil || cc.URL != "http://stats:8086" || cc.APIVersion != 1 || cc.V1.Database != "stress_stats" {
+		t.Errorf("Wrong connection of host and database. Got %+v, %v", cc, err)
+	}
+
+	c.StatsRecord.Connection = "stats"
+	if cc, err := c.StatsRecordConnection(); err != nil || cc.Name != "Stats" || cc.APIVersion != 2 {
+		t.Errorf("Wrong named connection. Got %+v, %v", cc, err)
+	}
+	c.StatsRecord.Connection = "nope"
+	if _, err := c.StatsRecordConnection(); err != utils.ErrNotFound {
+		t.Errorf("Expected not found, got %v", err)
+	}
+}
@@ -35,6 +35,20 @@ func (c *Config) FindDefaultInfluxDBConnection() (InfluxClientConfig, error) {
 
 }
 
+// FindDefaultPostgresConnection find default configuration from configured connections
+func (c *Config) FindDefaultPostgresConnection() (PostgresClientConfig, error) {
+	v := c.Connection.Postgres
+	for _, sc := range v {
+		if sc.Default {
+			return sc, nil
+		}
+	}
+	if len(v) > 0 {
+		return v[0], nil
+	}
+	return PostgresClientConfig{}, utils.ErrNotFound
+}
+
 // FindMySQLConnection find connnection by name
 func (c *Config) FindMySQLConnection(name string) (MySQLClientConfig, error) {
 	v := c.Connection.MySQL
@@ -43,7 +57,7 @@ func (c *Config) FindMySQLConnection(name string) (MySQLClientConfig, error) {
 			return sc, nil
 		}
 	}
-	return MySQLClientConfig{}, nil
+	return MySQLClientConfig{}, utils.ErrNotFound
 }
 
 // FindInfluxDBConnection find connnection by name
@@ -54,5 +68,27 @@ func (c *Config) FindInfluxDBConnection(name string) (InfluxClientConfig, error)
 			return sc, nil
 		}
 	}
-	return InfluxClientConfig{}, nil
+	return InfluxClientConfig{}, utils.ErrNotFound
+}
+
+// FindLineProtocolConnection find connnection by name
+func (c *Config) FindLineProtocolConnection(name string) (LineProtocolClientConfig, error) {
+	v := c.Connection.LineProtocol
+	for _, sc := range v {
+		if strings.EqualFold(sc.Name, name) {
+			return sc, nil
+		}
+	}
+	return LineProtocolClientConfig{}, utils.ErrNotFound
+}
+
+// FindPostgresConnection find connnection by name
+func (c *Config) FindPostgresConnection(name string) (PostgresClientConfig, error) {
+	v := c.Connection.Postgres
+	for _, sc := range v {
+		if strings.EqualFold(sc.Name, name) {
+			return sc, nil
+		}
+	}
+	return PostgresClientConfig{}, utils.ErrNotFound
 }
@@ -0,0 +1,123 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/deltacat/dbstress/data/fieldset"
+	"github.com/deltacat/dbstress/data/influx/lineprotocol"
+	"github.com/deltacat/dbstress/utils"
+)
+
+// write consistency levels of influxdb clusters
+var consistencies = []string{"", "any", "one", "quorum", "all"}
+
+// Validate check config, return all problems found.
+// Cases are not checked here since they are defined by runner.
+func (c *Config) Validate() []error {
+	errs := []error{}
+
+	seen := map[string]ConnectionInfo{}
+	defaults := map[string][]string{}
+	for _, ci := range c.ConnectionList() {
+		if ci.Name == "" {
+			errs = append(errs, fmt.Errorf("connection: %s connection at %q has no name", ci.Driver, ci.Addr))
+			continue
+		}
+		key := strings.ToLower(ci.Name)
+		if prev, ok := seen[key]; ok {
+			errs = append(errs, fmt.Errorf("connection %q: name duplicated, already used by %s connection", ci.Name, prev.Driver))
+		} else {
+			seen[key] = ci
+		}
+		if ci.Default {
+			defaults[ci.Driver] = append(defaults[ci.Driver], ci.Name)
+		}
+	}
+	for _, driver := range []string{DriverInfluxDB, DriverLineProtocol, DriverMySQL, DriverPostgres} {
+		if names := defaults[driver]; len(names) > 1 {
+			errs = append(errs, fmt.Errorf("connection: multiple default %s connections %v, only %q is used", driver, names, names[0]))
+		}
+	}
+
+	for _, cc := range c.Connection.InfluxDB {
+		errs = append(errs, validateTimeout(cc.Name, cc.Timeout)...)
+		if _, err := lineprotocol.ParsePrecision(cc.Precision); err != nil {
+			errs = append(errs, fmt.Errorf("connection %q: %v, expect n, u, ms or s", cc.Name, err))
+		}
+		if !utils.ArrayContainsStringIgnoreCase(consistencies, cc.Consistency) {
+			errs = append(errs, fmt.Errorf("connection %q: unknown consistency %q, expect any, one, quorum or all", cc.Name, cc.Consistency))
+		}
+		if cc.APIVersion != 0 && cc.APIVersion != 1 && cc.APIVersion != 2 {
+			errs = append(errs, fmt.Errorf("connection %q: unknown api-version %d, expect 1 or 2", cc.Name, cc.APIVersion))
+		}
+	}
+
+	for _, cc := range c.Connection.LineProtocol {
+		errs = append(errs, validateTimeout(cc.Name, cc.Timeout)...)
+		if _, err := lineprotocol.ParsePrecision(cc.Precision); err != nil {
+			errs = append(errs, fmt.Errorf("connection %q: %v, expect n, u, ms or s", cc.Name, err))
+		}
+		if !strings.HasPrefix(cc.WritePath, "/") {
+			errs = append(errs, fmt.Errorf("connection %q: write-path %q should start with /", cc.Name, cc.WritePath))
+		}
+		if cc.HealthPath != "" && !strings.HasPrefix(cc.HealthPath, "/") {
+			errs = append(errs, fmt.Errorf("connection %q: health-path %q should start with /", cc.Name, cc.HealthPath))
+		}
+	}
+
+	for _, cc := range c.Connection.MySQL {
+		errs = append(errs, validateTimeout(cc.Name, cc.Timeout)...)
+	}
+	for _, cc := range c.Connection.Postgres {
+		errs = append(errs, validateTimeout(cc.Name, cc.Timeout)...)
+	}
+
+	if sr := c.StatsRecord; sr.Enable && sr.Connection != "" {
+		if _, err := c.FindInfluxDBConnection(sr.Connection); err != nil {
+			errs = append(errs, fmt.Errorf("stats-record: unknown influxdb connection %q", sr.Connection))
+		}
+	}
+
+	if c.Cases.Interval < 0 {
+		errs = append(errs, fmt.Errorf("cases: interval %v should not be negative", c.Cases.Interval))
+	}
+	if f := c.Cases.IntervalsFormat; f != "" && f != "csv" && f != "json" {
+		errs = append(errs, fmt.Errorf("cases: unknown intervals-format %q, expect csv or json", f))
+	}
+
+	for _, err := range c.Points.Validate() {
+		errs = append(errs, fmt.Errorf("points: %v", err))
+	}
+	return errs
+}
+
+func validateTimeout(name string, timeout time.Duration) []error {
+	if timeout < 0 {
+		return []error{fmt.Errorf("connection %q: timeout %v should not be negative", name, timeout)}
+	}
+	return nil
+}
+
+// Validate check points template, fields not set are ignored
+func (p PointsConfig) Validate() []error {
+	errs := []error{}
+	if p.SeriesKey != "" {
+		if err := fieldset.ValidateTagsSet(p.SeriesKey); err != nil {
+			errs = append(errs, fmt.Errorf("series-key %q: %v", p.SeriesKey, err))
+		}
+	}
+	if p.FieldsStr != "" {
+		if err := fieldset.ValidateFieldSet(p.FieldsStr); err != nil {
+			errs = append(errs, fmt.Errorf("fields-str %q: %v", p.FieldsStr, err))
+		}
+	}
+	if strings.ContainsAny(p.Measurement, " ,") {
+		errs = append(errs, fmt.Errorf("measurement %q: should not contain space or comma", p.Measurement))
+	}
+	if p.SeriesN < 0 {
+		errs = append(errs, fmt.Errorf("series-num %d: should not be negative", p.SeriesN))
+	}
+	return errs
+}
@@ -0,0 +1,27 @@
+package config
+
+import (
+	"testing"
+)
+
+func TestConfig_Validate(t *testing.T) {
+	var c Config
+	c.Connection.InfluxDB = []InfluxClientConfig{
+		{Name: "Influx1", Default: true, Precision: "n", Consistency: "one"},
+		{Name: "influx1", Default: true, Precision: "h", Consistency: "most"},
+	}
+	c.Connection.LineProtocol = []LineProtocolClientConfig{{Name: "VM", Precision: "n", WritePath: "write"}}
+	c.Points = PointsConfig{Measurement: "ctr", SeriesKey: "ctr,some=tag", FieldsStr: "n=0i"}
+
+	// duplicated name, multiple defaults, precision, consistency, write-path and series-key
+	if got, exp := len(c.Validate()), 6; got != exp {
+		t.Errorf("Wrong number of problems. Got %v, Expected: %v\n%v", got, exp, c.Validate())
+	}
+
+	c.Connection.InfluxDB = c.Connection.InfluxDB[:1]
+	c.Connection.LineProtocol[0].WritePath = "/write"
+	c.Points.SeriesKey = "some=tag"
+	if errs := c.Validate(); len(errs) != 0 {
+		t.Errorf("Expected valid config, got %v", errs)
+	}
+}
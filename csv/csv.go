@@ -1,6 +1,7 @@
 package csv
 
 import (
+	"io"
 	"os"
 	"path"
 
@@ -8,20 +9,14 @@ import (
 )
 
 // Parse parse csv
-func Parse(path string, out interface{}) {
-	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, os.ModePerm)
+func Parse(path string, out interface{}) error {
+	file, err := os.Open(path)
 	if err != nil {
-		panic(err)
+		return err
 	}
-	defer func() {
-		if err := file.Close(); err != nil {
-			panic(err)
-		}
-	}()
+	defer file.Close()
 
-	if err := gocsv.UnmarshalFile(file, out); err != nil {
-		panic(err)
-	}
+	return gocsv.UnmarshalFile(file, out)
 }
 
 // Output write csv
@@ -48,3 +43,8 @@ func Output(filename string, data interface{}) error {
 
 	return nil
 }
+
+// Marshal write csv to writer
+func Marshal(w io.Writer, data interface{}) error {
+	return gocsv.Marshal(data, w)
+}
@@ -7,8 +7,12 @@ type Duration struct {
 	time.Duration
 }
 
-// UnmarshalCSV convert the CSV string as internal duration
+// UnmarshalCSV convert the CSV string as internal duration, empty as 0
 func (d *Duration) UnmarshalCSV(s string) (err error) {
+	if s == "" {
+		d.Duration = 0
+		return nil
+	}
 	d.Duration, err = time.ParseDuration(s)
 	return err
 }
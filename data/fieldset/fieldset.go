@@ -1,6 +1,10 @@
 package fieldset
 
-import "strings"
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
 
 // GenerateFieldSet ...
 func GenerateFieldSet(s string) ([]string, []string, []string) {
@@ -34,3 +38,44 @@ func GenerateTagsSet(tagsTmpl string) [][]string {
 	}
 	return tags
 }
+
+// ValidateFieldSet check fields template like "a=0i,b=0,c=str",
+// int values end with i, string values are str, others should be float
+func ValidateFieldSet(s string) error {
+	if s == "" {
+		return fmt.Errorf("empty fields")
+	}
+	for _, part := range strings.Split(s, ",") {
+		kv := strings.Split(part, "=")
+		if len(kv) != 2 || kv[0] == "" || strings.ContainsAny(kv[0], " \t") {
+			return fmt.Errorf("malformed field %q, expect key=value", part)
+		}
+		v := kv[1]
+		switch {
+		case v == "str":
+		case strings.HasSuffix(v, "i"):
+			if _, err := strconv.ParseInt(strings.TrimSuffix(v, "i"), 10, 64); err != nil {
+				return fmt.Errorf("malformed int field %q, expect value like 0i", part)
+			}
+		default:
+			if _, err := strconv.ParseFloat(v, 64); err != nil {
+				return fmt.Errorf("malformed field %q, expect value like 0i, 0.5 or str", part)
+			}
+		}
+	}
+	return nil
+}
+
+// ValidateTagsSet check tags template like "host=server,region=us"
+func ValidateTagsSet(tagsTmpl string) error {
+	if tagsTmpl == "" {
+		return fmt.Errorf("empty tags")
+	}
+	for _, part := range strings.Split(tagsTmpl, ",") {
+		kv := strings.Split(part, "=")
+		if len(kv) != 2 || kv[0] == "" || kv[1] == "" || strings.ContainsAny(part, " \t") {
+			return fmt.Errorf("malformed tag %q, expect key=value", part)
+		}
+	}
+	return nil
+}
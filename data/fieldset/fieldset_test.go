@@ -68,3 +68,29 @@ func TestGenerateFieldSet_mixedStr(t *testing.T) {
 		t.Errorf("Wrong float fields pulled. Got %v, Expected: %v\n", got, exp)
 	}
 }
+
+func TestValidateFieldSet(t *testing.T) {
+	for _, s := range []string{"n=0i", "a=1i,b=0.5,c=str", "f=-3"} {
+		if err := ValidateFieldSet(s); err != nil {
+			t.Errorf("Expected %q valid, got %v", s, err)
+		}
+	}
+	for _, s := range []string{"", "n", "=0i", "n=xi", "n=abc", "a=0i,,b=0", "a b=0"} {
+		if err := ValidateFieldSet(s); err == nil {
+			t.Errorf("Expected %q invalid", s)
+		}
+	}
+}
+
+func TestValidateTagsSet(t *testing.T) {
+	for _, s := range []string{"some=tag", "some=tag,other=tag"} {
+		if err := ValidateTagsSet(s); err != nil {
+			t.Errorf("Expected %q valid, got %v", s, err)
+		}
+	}
+	for _, s := range []string{"", "ctr,some=tag", "some=", "some=tag,", "a=b=c"} {
+		if err := ValidateTagsSet(s); err == nil {
+			t.Errorf("Expected %q invalid", s)
+		}
+	}
+}
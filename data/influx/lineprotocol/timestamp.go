@@ -1,6 +1,7 @@
 package lineprotocol
 
 import (
+	"fmt"
 	"io"
 	"strconv"
 	"sync/atomic"
@@ -15,10 +16,28 @@ type Precision int
 const (
 	Nanosecond = iota
 	Second
+	Millisecond
+	Microsecond
 )
 
+// ParsePrecision parse precision param of influxdb write api, n, ns, u, us, ms or s.
+// Empty is nanosecond.
+func ParsePrecision(s string) (Precision, error) {
+	switch s {
+	case "", "n", "ns":
+		return Nanosecond, nil
+	case "u", "us":
+		return Microsecond, nil
+	case "ms":
+		return Millisecond, nil
+	case "s":
+		return Second, nil
+	}
+	return Nanosecond, fmt.Errorf("unknown precision %q", s)
+}
+
 // Timestamp represents a timestamp in line protocol
-// in either second or nanosecond precision.
+// in second, millisecond, microsecond or nanosecond precision.
 type Timestamp struct {
 	precision Precision
 	ptr       unsafe.Pointer
@@ -51,8 +70,13 @@ func (t *Timestamp) WriteTo(w io.Writer) (int64, error) {
 	tsTime := *(*time.Time)(tsPtr)
 	ts := tsTime.UnixNano()
 
-	if t.precision == Second {
+	switch t.precision {
+	case Second:
 		ts = tsTime.Unix()
+	case Millisecond:
+		ts /= int64(time.Millisecond)
+	case Microsecond:
+		ts /= int64(time.Microsecond)
 	}
 
 	// Max int64 fits in 19 base-10 digits;
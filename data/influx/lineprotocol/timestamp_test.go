@@ -62,3 +62,46 @@ func TestTimestamp_WriteTo_Nanosecond(t *testing.T) {
 		return
 	}
 }
+
+func TestTimestamp_WriteTo_Millisecond(t *testing.T) {
+	ts := lineprotocol.NewTimestamp(lineprotocol.Millisecond)
+	ts.SetTime(&testTime)
+
+	buf := bytes.NewBuffer(nil)
+	if _, err := ts.WriteTo(buf); err != nil {
+		t.Error(err)
+		return
+	}
+
+	exp := fmt.Sprintf("%v", testTime.UnixNano()/int64(time.Millisecond))
+	got := string(buf.Bytes())
+
+	if got != exp {
+		t.Errorf("Wrong timestamp written. got %v, exp %v", got, exp)
+		return
+	}
+}
+
+func TestParsePrecision(t *testing.T) {
+	tests := []struct {
+		s   string
+		exp lineprotocol.Precision
+		err bool
+	}{
+		{"", lineprotocol.Nanosecond, false},
+		{"n", lineprotocol.Nanosecond, false},
+		{"us", lineprotocol.Microsecond, false},
+		{"ms", lineprotocol.Millisecond, false},
+		{"s", lineprotocol.Second, false},
+		{"h", lineprotocol.Nanosecond, true},
+	}
+	for _, test := range tests {
+		got, err := lineprotocol.ParsePrecision(test.s)
+		if (err != nil) != test.err {
+			t.Errorf("Unexpected error of %q: %v", test.s, err)
+		}
+		if got != test.exp {
+			t.Errorf("Wrong precision of %q. got %v, exp %v", test.s, got, test.exp)
+		}
+	}
+}
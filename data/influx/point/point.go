@@ -113,3 +113,8 @@ func NewPoints(measurement, seriesKey, fields string, seriesN int, pc lineprotoc
 
 	return pts
 }
+
+// NewSeriesKeys returns seriesN series keys shaped like the given seriesKey, same as NewPoints uses.
+func NewSeriesKeys(measurement, seriesKey string, seriesN int) [][]byte {
+	return generateSeriesKeys(measurement, seriesKey, seriesN)
+}
@@ -8,6 +8,9 @@ import (
 	"github.com/deltacat/dbstress/data/fieldset"
 )
 
+// TagValueCardinality number of distinct values of each tag column
+const TagValueCardinality = 300
+
 // Layout mysql table layout definition
 type Layout struct {
 	name    string
@@ -78,7 +81,7 @@ func (l *Layout) genRow(intVal int, floatVal float32, str string, rd *rand.Rand)
 		r.AppendCol("'" + sv + "'")
 	}
 	for _, t := range l.tags {
-		r.AppendCol(fmt.Sprintf("'%s-%d'", t[1], rd.Int31n(300)))
+		r.AppendCol(fmt.Sprintf("'%s-%d'", t[1], rd.Int31n(TagValueCardinality)))
 	}
 	return r
 }
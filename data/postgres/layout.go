@@ -0,0 +1,136 @@
+package postgres
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+
+	"github.com/deltacat/dbstress/data/fieldset"
+)
+
+// TagValueCardinality number of distinct values of each tag column
+const TagValueCardinality = 300
+
+// Layout postgres table layout definition
+type Layout struct {
+	name       string
+	ints       []string
+	floats     []string
+	strs       []string
+	tags       [][]string // mirror influxdb tags as indexed columns, [0] is column name, [1] is value prefix
+	hypertable bool
+}
+
+// GetCreateStmt get create table DDL
+func (l *Layout) GetCreateStmt() string {
+	stmts := []string{}
+	if l.hypertable {
+		// timescaledb requires the partitioning column in every unique index, so no serial primary key here
+		stmts = append(stmts,
+			fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (%s, create_time TIMESTAMPTZ NOT NULL DEFAULT now())", l.name, l.genColumnDDL()),
+			fmt.Sprintf("SELECT create_hypertable('%s', 'create_time', if_not_exists => TRUE)", l.name))
+	} else {
+		stmts = append(stmts,
+			fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (id BIGSERIAL PRIMARY KEY, %s, create_time TIMESTAMPTZ NOT NULL DEFAULT now())", l.name, l.genColumnDDL()),
+			fmt.Sprintf("CREATE INDEX IF NOT EXISTS %s_time_idx ON %s (create_time)", l.name, l.name))
+	}
+	if idx := l.genIndexDDL(); idx != "" {
+		stmts = append(stmts, idx)
+	}
+	return strings.Join(stmts, "; ") + ";"
+}
+
+// GenInsertStmtValues generate insert row DML, text columns are quoted
+func (l *Layout) GenInsertStmtValues(colVals []string) string {
+	numerics := len(l.ints) + len(l.floats)
+	vals := make([]string, len(colVals))
+	for i, v := range colVals {
+		if i >= numerics {
+			v = "'" + v + "'"
+		}
+		vals[i] = v
+	}
+	return "(" + strings.Join(vals, ",") + ")"
+}
+
+// GetCopyStmt get the COPY statement which rows are streamed into
+func (l *Layout) GetCopyStmt() string {
+	return fmt.Sprintf("COPY %s (%s) FROM STDIN", l.name, strings.Join(l.columns(), ", "))
+}
+
+func (l *Layout) columns() []string {
+	cols := []string{}
+	cols = append(cols, l.ints...)
+	cols = append(cols, l.floats...)
+	cols = append(cols, l.strs...)
+	for _, t := range l.tags {
+		cols = append(cols, t[0])
+	}
+	return cols
+}
+
+func (l *Layout) genColumnDDL() string {
+	cols := []string{}
+	for _, s := range l.ints {
+		cols = append(cols, s+" INTEGER")
+	}
+	for _, s := range l.floats {
+		cols = append(cols, s+" REAL")
+	}
+	for _, s := range l.strs {
+		cols = append(cols, s+" CHAR(64)")
+	}
+	for _, s := range l.tags {
+		cols = append(cols, s[0]+" VARCHAR(32) NOT NULL DEFAULT ''")
+	}
+	if len(cols) > 0 {
+		return strings.Join(cols, ", ")
+	}
+	return ""
+}
+
+func (l *Layout) genIndexDDL() string {
+	ids := []string{}
+	for _, s := range l.tags {
+		ids = append(ids, s[0])
+	}
+	if len(ids) > 0 {
+		return fmt.Sprintf("CREATE INDEX IF NOT EXISTS %s_ss_idx ON %s (%s)", l.name, l.name, strings.Join(ids, ", "))
+	}
+	return ""
+}
+
+func (l *Layout) genRow(intVal int, floatVal float32, str string, rd *rand.Rand) Row {
+	r := Row{}
+	iv := intVal
+	fv := floatVal
+	for range l.ints {
+		iv++
+		r.AppendCol(iv)
+	}
+	for range l.floats {
+		fv += 0.1
+		r.AppendCol(fv)
+	}
+	for range l.strs {
+		r.AppendCol(str)
+	}
+	for _, t := range l.tags {
+		r.AppendCol(fmt.Sprintf("%s-%d", t[1], rd.Int31n(TagValueCardinality)))
+	}
+	return r
+}
+
+// GenerateLayout generate a new layout, hypertable makes it a timescaledb hypertable
+func GenerateLayout(measurement, tagsStr, fieldsStr string, hypertable bool) (Layout, error) {
+	ints, floats, strs := fieldset.GenerateFieldSet(fieldsStr)
+	tags := fieldset.GenerateTagsSet(tagsStr)
+	return Layout{
+		name:       measurement,
+		ints:       ints,
+		floats:     floats,
+		strs:       strs,
+		tags:       tags,
+		hypertable: hypertable,
+	}, nil
+}
@@ -0,0 +1,260 @@
+// Package query generates read queries shaped like the points written by data/influx/point and data/mysql.
+package query
+
+import (
+	"bytes"
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/deltacat/dbstress/data/fieldset"
+	"github.com/deltacat/dbstress/utils"
+)
+
+// Dialect query language accepted by the target database
+type Dialect string
+
+// supported dialects
+const (
+	InfluxQL Dialect = "influxql"
+	Flux     Dialect = "flux"
+	MySQL    Dialect = "mysql"
+	Postgres Dialect = "postgres"
+)
+
+// builtin templates
+const (
+	TemplateLast    = "last"     // last point of a random series
+	TemplateRange   = "range"    // time-range aggregate of a random series
+	TemplateGroupBy = "group-by" // aggregate over time range grouped by the first tag
+)
+
+// DefaultTemplates templates used when none configured
+var DefaultTemplates = []string{TemplateLast, TemplateRange, TemplateGroupBy}
+
+const (
+	defaultWindow   = 5 * time.Minute
+	defaultInterval = time.Minute
+)
+
+// Tag a tag (or indexed column) key value pair of a series
+type Tag struct {
+	Key   string
+	Value string
+}
+
+// Generator generate queries against the configured series
+type Generator struct {
+	dialect     Dialect
+	bucket      string
+	measurement string
+	field       string
+	aggregate   bool // field is numeric, otherwise only count is possible
+	series      [][]Tag
+	templates   []string
+	window      time.Duration
+	interval    time.Duration
+
+	mu sync.Mutex
+	rr *rand.Rand
+}
+
+// NewGenerator create a query generator.
+// Templates are builtin template names or custom query text, in which
+// $measurement, $field, $bucket, $tags (filter of a random series) and $seconds (window) are replaced.
+func NewGenerator(dialect Dialect, bucket, measurement, fieldsStr string, series [][]Tag, templates []string, window, interval time.Duration) (*Generator, error) {
+	if len(series) == 0 {
+		return nil, utils.ErrInvalidArgs
+	}
+	if len(templates) == 0 {
+		templates = DefaultTemplates
+	}
+	if window < time.Second {
+		window = defaultWindow
+	}
+	if interval < time.Second {
+		interval = defaultInterval
+	}
+	g := &Generator{
+		dialect:     dialect,
+		bucket:      bucket,
+		measurement: measurement,
+		series:      series,
+		templates:   templates,
+		window:      window,
+		interval:    interval,
+		rr:          rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+	ints, floats, strs := fieldset.GenerateFieldSet(fieldsStr)
+	switch {
+	case len(ints) > 0:
+		g.field, g.aggregate = ints[0], true
+	case len(floats) > 0:
+		g.field, g.aggregate = floats[0], true
+	case len(strs) > 0:
+		g.field = strs[0]
+	default:
+		return nil, utils.ErrInvalidArgs
+	}
+	return g, nil
+}
+
+// Next return next query and the template it generated from
+func (g *Generator) Next() (string, string) {
+	g.mu.Lock()
+	tmpl := g.templates[g.rr.Intn(len(g.templates))]
+	series := g.series[g.rr.Intn(len(g.series))]
+	g.mu.Unlock()
+
+	switch tmpl {
+	case TemplateLast:
+		return tmpl, g.last(series)
+	case TemplateRange:
+		return tmpl, g.rangeAggregate(series)
+	case TemplateGroupBy:
+		return tmpl, g.groupBy(series)
+	}
+	return "custom", strings.NewReplacer(
+		"$measurement", g.measurement,
+		"$field", g.field,
+		"$bucket", g.bucket,
+		"$tags", g.filter(series),
+		"$seconds", strconv.Itoa(int(g.window.Seconds())),
+	).Replace(tmpl)
+}
+
+func (g *Generator) fn() string {
+	if !g.aggregate {
+		return "count"
+	}
+	if g.dialect == InfluxQL || g.dialect == Flux {
+		return "mean"
+	}
+	return "avg"
+}
+
+func (g *Generator) filter(series []Tag) string {
+	conds := []string{}
+	for _, t := range series {
+		switch g.dialect {
+		case Flux:
+			conds = append(conds, fmt.Sprintf(`r.%s == "%s"`, t.Key, t.Value))
+		default:
+			conds = append(conds, fmt.Sprintf(`%s='%s'`, t.Key, t.Value))
+		}
+	}
+	if g.dialect == Flux {
+		return strings.Join(conds, " and ")
+	}
+	return strings.Join(conds, " AND ")
+}
+
+func (g *Generator) sqlSince() string {
+	secs := int(g.window.Seconds())
+	if g.dialect == Postgres {
+		return fmt.Sprintf("create_time > now() - interval '%d seconds'", secs)
+	}
+	return fmt.Sprintf("create_time > NOW() - INTERVAL %d SECOND", secs)
+}
+
+func (g *Generator) sqlBucket() string {
+	secs := int(g.interval.Seconds())
+	if g.dialect == Postgres {
+		return fmt.Sprintf("floor(extract(epoch from create_time) / %d)", secs)
+	}
+	return fmt.Sprintf("FLOOR(UNIX_TIMESTAMP(create_time) / %d)", secs)
+}
+
+func (g *Generator) fluxFrom() string {
+	return fmt.Sprintf(`from(bucket: "%s") |> range(start: -%s) |> filter(fn: (r) => r._measurement == "%s"`,
+		g.bucket, durationLiteral(g.window), g.measurement)
+}
+
+func (g *Generator) last(series []Tag) string {
+	switch g.dialect {
+	case InfluxQL:
+		return fmt.Sprintf("SELECT LAST(*) FROM %s WHERE %s", g.measurement, g.filter(series))
+	case Flux:
+		return fmt.Sprintf("%s and %s) |> last()", g.fluxFrom(), g.filter(series))
+	default:
+		return fmt.Sprintf("SELECT * FROM %s WHERE %s ORDER BY create_time DESC LIMIT 1", g.measurement, g.filter(series))
+	}
+}
+
+func (g *Generator) rangeAggregate(series []Tag) string {
+	switch g.dialect {
+	case InfluxQL:
+		return fmt.Sprintf("SELECT %s(%s) FROM %s WHERE %s AND time > now() - %s GROUP BY time(%s)",
+			strings.ToUpper(g.fn()), g.field, g.measurement, g.filter(series), durationLiteral(g.window), durationLiteral(g.interval))
+	case Flux:
+		return fmt.Sprintf(`%s and r._field == "%s" and %s) |> aggregateWindow(every: %s, fn: %s)`,
+			g.fluxFrom(), g.field, g.filter(series), durationLiteral(g.interval), g.fn())
+	default:
+		return fmt.Sprintf("SELECT %s AS bucket, %s(%s) FROM %s WHERE %s AND %s GROUP BY bucket",
+			g.sqlBucket(), strings.ToUpper(g.fn()), g.field, g.measurement, g.filter(series), g.sqlSince())
+	}
+}
+
+func (g *Generator) groupBy(series []Tag) string {
+	key := series[0].Key
+	switch g.dialect {
+	case InfluxQL:
+		return fmt.Sprintf("SELECT %s(%s) FROM %s WHERE time > now() - %s GROUP BY %s",
+			strings.ToUpper(g.fn()), g.field, g.measurement, durationLiteral(g.window), key)
+	case Flux:
+		return fmt.Sprintf(`%s and r._field == "%s") |> group(columns: ["%s"]) |> %s()`,
+			g.fluxFrom(), g.field, key, g.fn())
+	default:
+		return fmt.Sprintf("SELECT %s, %s(%s) FROM %s WHERE %s GROUP BY %s",
+			key, strings.ToUpper(g.fn()), g.field, g.measurement, g.sqlSince(), key)
+	}
+}
+
+// durationLiteral format duration as seconds literal, accepted by both influxql and flux
+func durationLiteral(d time.Duration) string {
+	return fmt.Sprintf("%ds", int(d.Seconds()))
+}
+
+// SeriesFromKeys parse line protocol series keys (as generated by data/influx/point) into tags
+func SeriesFromKeys(keys [][]byte) [][]Tag {
+	series := [][]Tag{}
+	for _, key := range keys {
+		parts := bytes.Split(key, []byte(","))
+		tags := []Tag{}
+		for _, part := range parts[1:] {
+			kv := bytes.SplitN(part, []byte("="), 2)
+			if len(kv) != 2 {
+				continue
+			}
+			tags = append(tags, Tag{Key: string(kv[0]), Value: string(kv[1])})
+		}
+		if len(tags) > 0 {
+			series = append(series, tags)
+		}
+	}
+	return series
+}
+
+// SampleSeries sample n series from tags template (as used by data/mysql layout),
+// every tag value is "<prefix>-<0..card-1>"
+func SampleSeries(tagsTmpl string, card, n int) [][]Tag {
+	rr := rand.New(rand.NewSource(time.Now().UnixNano()))
+	tagsSet := fieldset.GenerateTagsSet(tagsTmpl)
+	series := [][]Tag{}
+	for i := 0; i < n; i++ {
+		tags := []Tag{}
+		for _, t := range tagsSet {
+			if len(t) != 2 {
+				continue
+			}
+			tags = append(tags, Tag{Key: t[0], Value: fmt.Sprintf("%s-%d", t[1], rr.Intn(card))})
+		}
+		if len(tags) > 0 {
+			series = append(series, tags)
+		}
+	}
+	return series
+}
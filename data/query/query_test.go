@@ -0,0 +1,57 @@
+package query
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSeriesFromKeys(t *testing.T) {
+	got := SeriesFromKeys([][]byte{[]byte("ctr,some=tag-0,other=tag-1")})
+	exp := [][]Tag{{{Key: "some", Value: "tag-0"}, {Key: "other", Value: "tag-1"}}}
+	if !reflect.DeepEqual(got, exp) {
+		t.Errorf("Wrong series parsed. Got %v, Expected: %v\n", got, exp)
+	}
+}
+
+func TestGenerator_Next(t *testing.T) {
+	series := [][]Tag{{{Key: "some", Value: "tag-0"}}}
+	tests := []struct {
+		dialect  Dialect
+		template string
+		exp      string
+	}{
+		{InfluxQL, TemplateLast, "SELECT LAST(*) FROM ctr WHERE some='tag-0'"},
+		{InfluxQL, TemplateRange, "SELECT MEAN(n) FROM ctr WHERE some='tag-0' AND time > now() - 300s GROUP BY time(60s)"},
+		{InfluxQL, TemplateGroupBy, "SELECT MEAN(n) FROM ctr WHERE time > now() - 300s GROUP BY some"},
+		{Flux, TemplateLast, `from(bucket: "stress") |> range(start: -300s) |> filter(fn: (r) => r._measurement == "ctr" and r.some == "tag-0") |> last()`},
+		{MySQL, TemplateLast, "SELECT * FROM ctr WHERE some='tag-0' ORDER BY create_time DESC LIMIT 1"},
+		{MySQL, TemplateGroupBy, "SELECT some, AVG(n) FROM ctr WHERE create_time > NOW() - INTERVAL 300 SECOND GROUP BY some"},
+		{Postgres, TemplateGroupBy, "SELECT some, AVG(n) FROM ctr WHERE create_time > now() - interval '300 seconds' GROUP BY some"},
+		{MySQL, "SELECT COUNT($field) FROM $measurement WHERE $tags", "SELECT COUNT(n) FROM ctr WHERE some='tag-0'"},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.dialect)+"/"+tt.template, func(t *testing.T) {
+			g, err := NewGenerator(tt.dialect, "stress", "ctr", "n=0i,log=str", series, []string{tt.template}, 5*time.Minute, time.Minute)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, got := g.Next(); got != tt.exp {
+				t.Errorf("Wrong query generated. Got %v, Expected: %v\n", got, tt.exp)
+			}
+		})
+	}
+}
+
+func TestSampleSeries(t *testing.T) {
+	series := SampleSeries("some=tag,other=tag", 300, 10)
+	if len(series) != 10 {
+		t.Fatalf("Wrong series number. Got %v, Expected: %v\n", len(series), 10)
+	}
+	for _, s := range series {
+		if len(s) != 2 || s[0].Key != "some" || !strings.HasPrefix(s[0].Value, "tag-") {
+			t.Errorf("Wrong series sampled: %v\n", s)
+		}
+	}
+}
@@ -0,0 +1,167 @@
+package report
+
+import (
+	"fmt"
+	"html"
+	"html/template"
+	"math"
+	"strings"
+)
+
+// chart layout in pixels
+const (
+	chartWidth       = 720
+	chartHeight      = 240
+	chartPadLeft     = 64
+	chartPadRight    = 16
+	chartPadTop      = 12
+	chartPadBottom   = 40
+	chartTicks       = 5
+	chartLegendWidth = 120
+)
+
+var chartColors = []string{"#1f77b4", "#ff7f0e", "#2ca02c", "#d62728", "#9467bd", "#8c564b"}
+
+// chartSeries a named line (or bars) of a chart
+type chartSeries struct {
+	Name string
+	X, Y []float64
+}
+
+// lineChart draw series as inline svg line chart, y axis starts from 0
+func lineChart(series []chartSeries, xLabel string, yFmt func(float64) string) template.HTML {
+	var maxX, maxY float64
+	for _, s := range series {
+		for i := range s.X {
+			maxX = math.Max(maxX, s.X[i])
+			maxY = math.Max(maxY, s.Y[i])
+		}
+	}
+	if maxX == 0 {
+		maxX = 1
+	}
+	maxY = niceCeil(maxY)
+
+	b := &strings.Builder{}
+	plotW, plotH := chartPlotSize()
+	x := func(v float64) float64 { return chartPadLeft + v/maxX*plotW }
+	y := func(v float64) float64 { return chartPadTop + plotH - v/maxY*plotH }
+
+	chartBegin(b)
+	chartYAxis(b, maxY, yFmt)
+	for i := 0; i <= chartTicks; i++ {
+		v := maxX * float64(i) / chartTicks
+		fmt.Fprintf(b, `<text x="%.1f" y="%d" text-anchor="middle">%s</text>`, x(v), chartPadTop+int(plotH)+16, fmtCount(v))
+	}
+	fmt.Fprintf(b, `<text x="%.1f" y="%d" text-anchor="middle">%s</text>`, x(maxX/2), chartHeight-4, html.EscapeString(xLabel))
+
+	names := []string{}
+	for i, s := range series {
+		points := make([]string, len(s.X))
+		for j := range s.X {
+			points[j] = fmt.Sprintf("%.1f,%.1f", x(s.X[j]), y(s.Y[j]))
+		}
+		fmt.Fprintf(b, `<polyline fill="none" stroke="%s" stroke-width="1.5" points="%s"/>`, chartColor(i), strings.Join(points, " "))
+		names = append(names, s.Name)
+	}
+	chartLegend(b, names)
+	b.WriteString("</svg>")
+	return template.HTML(b.String())
+}
+
+// barChart draw series as inline svg grouped bar chart, a group per label, a bar per series in each group
+func barChart(labels []string, series []chartSeries, yFmt func(float64) string) template.HTML {
+	var maxY float64
+	for _, s := range series {
+		for _, v := range s.Y {
+			maxY = math.Max(maxY, v)
+		}
+	}
+	maxY = niceCeil(maxY)
+
+	b := &strings.Builder{}
+	plotW, plotH := chartPlotSize()
+	groupW := plotW / float64(len(labels))
+	barW := groupW * 0.8 / float64(len(series))
+
+	chartBegin(b)
+	chartYAxis(b, maxY, yFmt)
+	for i, label := range labels {
+		gx := chartPadLeft + groupW*float64(i) + groupW*0.1
+		fmt.Fprintf(b, `<text x="%.1f" y="%d" text-anchor="middle">%s</text>`, gx+groupW*0.4, chartPadTop+int(plotH)+16, html.EscapeString(label))
+		for j, s := range series {
+			h := s.Y[i] / maxY * plotH
+			fmt.Fprintf(b, `<rect x="%.1f" y="%.1f" width="%.1f" height="%.1f" fill="%s"><title>%s %s: %s</title></rect>`,
+				gx+barW*float64(j), chartPadTop+plotH-h, barW, h, chartColor(j),
+				html.EscapeString(s.Name), html.EscapeString(label), html.EscapeString(yFmt(s.Y[i])))
+		}
+	}
+	names := []string{}
+	for _, s := range series {
+		names = append(names, s.Name)
+	}
+	chartLegend(b, names)
+	b.WriteString("</svg>")
+	return template.HTML(b.String())
+}
+
+func chartPlotSize() (float64, float64) {
+	return chartWidth - chartPadLeft - chartPadRight - chartLegendWidth, chartHeight - chartPadTop - chartPadBottom
+}
+
+func chartBegin(b *strings.Builder) {
+	fmt.Fprintf(b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-size="11" font-family="sans-serif">`,
+		chartWidth, chartHeight, chartWidth, chartHeight)
+}
+
+// chartYAxis draw horizontal grid lines with y values
+func chartYAxis(b *strings.Builder, maxY float64, yFmt func(float64) string) {
+	plotW, plotH := chartPlotSize()
+	for i := 0; i <= chartTicks; i++ {
+		v := maxY * float64(i) / chartTicks
+		py := chartPadTop + plotH - plotH*float64(i)/chartTicks
+		fmt.Fprintf(b, `<line x1="%d" y1="%.1f" x2="%.1f" y2="%.1f" stroke="#ddd"/>`, chartPadLeft, py, chartPadLeft+plotW, py)
+		fmt.Fprintf(b, `<text x="%d" y="%.1f" text-anchor="end">%s</text>`, chartPadLeft-6, py+4, html.EscapeString(yFmt(v)))
+	}
+}
+
+func chartLegend(b *strings.Builder, names []string) {
+	lx := chartWidth - chartPadRight - chartLegendWidth + 12
+	for i, name := range names {
+		ly := chartPadTop + 14*i
+		fmt.Fprintf(b, `<rect x="%d" y="%d" width="10" height="10" fill="%s"/>`, lx, ly, chartColor(i))
+		fmt.Fprintf(b, `<text x="%d" y="%d">%s</text>`, lx+14, ly+9, html.EscapeString(name))
+	}
+}
+
+func chartColor(i int) string {
+	return chartColors[i%len(chartColors)]
+}
+
+// niceCeil round v up to 1, 2 or 5 times a power of 10
+func niceCeil(v float64) float64 {
+	if v <= 0 {
+		return 1
+	}
+	exp := math.Pow(10, math.Floor(math.Log10(v)))
+	switch f := v / exp; {
+	case f <= 1:
+		return exp
+	case f <= 2:
+		return 2 * exp
+	case f <= 5:
+		return 5 * exp
+	}
+	return 10 * exp
+}
+
+// fmtCount format count with k/M suffix
+func fmtCount(v float64) string {
+	switch {
+	case v >= 1e6:
+		return strings.TrimSuffix(fmt.Sprintf("%.1f", v/1e6), ".0") + "M"
+	case v >= 1e3:
+		return strings.TrimSuffix(fmt.Sprintf("%.1f", v/1e3), ".0") + "k"
+	}
+	return strings.TrimSuffix(fmt.Sprintf("%.1f", v), ".0")
+}
@@ -0,0 +1,134 @@
+package report
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/olekukonko/tablewriter"
+)
+
+// compared metrics
+const (
+	MetricThroughput  = "throughput"
+	MetricFailureRate = "failure rate"
+	MetricP50         = "p50"
+	MetricP90         = "p90"
+	MetricP99         = "p99"
+	MetricP999        = "p999"
+)
+
+// Delta change of a metric of a case between two runs
+type Delta struct {
+	Case       string
+	Action     string
+	Metric     string
+	Base       float64
+	Current    float64
+	Change     float64 // percent, or percentage points for failure rate
+	Regression bool
+	Missing    bool // case not found in current run
+}
+
+// Compare compare current run against base run.
+// A metric regresses when throughput drops, or latency grows, by more than threshold percent,
+// or failure rate grows by more than threshold percentage points.
+func Compare(base, current Result, threshold float64) []Delta {
+	curs := map[string]Record{}
+	for _, r := range current.Records {
+		curs[recordKey(r)] = r
+	}
+
+	deltas := []Delta{}
+	for _, b := range base.Records {
+		c, ok := curs[recordKey(b)]
+		if !ok {
+			deltas = append(deltas, Delta{Case: b.Case, Action: b.Action, Missing: true, Regression: true})
+			continue
+		}
+		add := func(metric string, bv, cv float64, higherIsBetter bool) {
+			d := Delta{Case: b.Case, Action: b.Action, Metric: metric, Base: bv, Current: cv}
+			d.Change = changePercent(bv, cv)
+			if higherIsBetter {
+				d.Regression = d.Change < -threshold
+			} else {
+				d.Regression = d.Change > threshold
+			}
+			deltas = append(deltas, d)
+		}
+		add(MetricThroughput, float64(b.Throughput), float64(c.Throughput), true)
+
+		fb, fc := failureRate(b), failureRate(c)
+		deltas = append(deltas, Delta{
+			Case: b.Case, Action: b.Action, Metric: MetricFailureRate,
+			Base: fb, Current: fc, Change: fc - fb, Regression: fc-fb > threshold,
+		})
+
+		add(MetricP50, b.LatP50Ms, c.LatP50Ms, false)
+		add(MetricP90, b.LatP90Ms, c.LatP90Ms, false)
+		add(MetricP99, b.LatP99Ms, c.LatP99Ms, false)
+		add(MetricP999, b.LatP999Ms, c.LatP999Ms, false)
+	}
+	return deltas
+}
+
+// HasRegression check if any delta is a regression
+func HasRegression(deltas []Delta) bool {
+	for _, d := range deltas {
+		if d.Regression {
+			return true
+		}
+	}
+	return false
+}
+
+// RenderDeltas render deltas as table
+func RenderDeltas(w io.Writer, deltas []Delta) {
+	table := tablewriter.NewWriter(w)
+	table.SetBorders(tablewriter.Border{Left: true, Top: false, Right: true, Bottom: false})
+	table.SetCenterSeparator("|")
+	table.SetHeader([]string{"case", "action", "metric", "base", "current", "change", "status"})
+	for _, d := range deltas {
+		if d.Missing {
+			table.Append([]string{d.Case, d.Action, "-", "-", "-", "-", "MISSING"})
+			continue
+		}
+		status := "ok"
+		if d.Regression {
+			status = "REGRESSION"
+		}
+		change := fmt.Sprintf("%+.1f%%", d.Change)
+		base, current := fmt.Sprintf("%.0f", d.Base), fmt.Sprintf("%.0f", d.Current)
+		switch d.Metric {
+		case MetricFailureRate:
+			change = fmt.Sprintf("%+.2fpp", d.Change)
+			base, current = fmt.Sprintf("%.2f%%", d.Base), fmt.Sprintf("%.2f%%", d.Current)
+		case MetricThroughput:
+		default:
+			base, current = fmtMs(d.Base), fmtMs(d.Current)
+		}
+		table.Append([]string{d.Case, d.Action, d.Metric, base, current, change, status})
+	}
+	table.Render()
+}
+
+func recordKey(r Record) string {
+	return r.Case + "/" + r.Action
+}
+
+// failureRate return failed points in percent
+func failureRate(r Record) float64 {
+	if r.Points == 0 {
+		return 0
+	}
+	return float64(r.Failed) / float64(r.Points) * 100
+}
+
+func changePercent(base, current float64) float64 {
+	if base == 0 {
+		if current == 0 {
+			return 0
+		}
+		return 100
+	}
+	return (current - base) / base * 100
+}
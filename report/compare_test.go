@@ -0,0 +1,50 @@
+package report
+
+import (
+	"testing"
+)
+
+func TestCompare(t *testing.T) {
+	base := Result{Records: []Record{
+		{Case: "influx", Action: "insert", Throughput: 1000, Points: 10000, Failed: 0, LatP50Ms: 10, LatP90Ms: 20, LatP99Ms: 40, LatP999Ms: 80},
+		{Case: "mysql", Action: "insert", Throughput: 1000, Points: 10000},
+	}}
+	current := Result{Records: []Record{
+		{Case: "influx", Action: "insert", Throughput: 850, Points: 10000, Failed: 50, LatP50Ms: 10, LatP90Ms: 21, LatP99Ms: 60, LatP999Ms: 80},
+	}}
+
+	deltas := Compare(base, current, 10)
+	got := map[string]Delta{}
+	for _, d := range deltas {
+		got[d.Case+"/"+d.Metric] = d
+	}
+
+	tests := []struct {
+		key        string
+		change     float64
+		regression bool
+	}{
+		{"influx/" + MetricThroughput, -15, true},
+		{"influx/" + MetricFailureRate, 0.5, false},
+		{"influx/" + MetricP50, 0, false},
+		{"influx/" + MetricP90, 5, false},
+		{"influx/" + MetricP99, 50, true},
+	}
+	for _, tt := range tests {
+		d, ok := got[tt.key]
+		if !ok {
+			t.Errorf("Missing delta %s", tt.key)
+			continue
+		}
+		if d.Change != tt.change || d.Regression != tt.regression {
+			t.Errorf("Wrong delta %s. Got %v/%v, Expected: %v/%v\n", tt.key, d.Change, d.Regression, tt.change, tt.regression)
+		}
+	}
+
+	if d, ok := got["mysql/"]; !ok || !d.Missing || !d.Regression {
+		t.Errorf("Case missing in current run should be a regression, got %+v", d)
+	}
+	if !HasRegression(deltas) {
+		t.Errorf("Expected regression")
+	}
+}
@@ -0,0 +1,50 @@
+package report
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/olekukonko/tablewriter"
+)
+
+// ErrorClass failed requests of a class, e.g. timeout, network, "http 503" or "mysql 1213"
+type ErrorClass struct {
+	Class   string   `json:"class"`
+	Count   uint64   `json:"count"`   // failed requests, including attempts which were retried
+	Retried uint64   `json:"retried"` // failed attempts which were retried
+	Points  uint64   `json:"points"`  // points of failed requests
+	Samples []string `json:"samples,omitempty"`
+}
+
+// HasErrors check if any record has failed requests
+func HasErrors(recs []Record) bool {
+	for _, r := range recs {
+		if len(r.Errors) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// RenderErrors render failed requests of records by class as table, with the first sample of each class
+func RenderErrors(w io.Writer, recs []Record) {
+	table := tablewriter.NewWriter(w)
+	table.SetBorders(tablewriter.Border{Left: true, Top: false, Right: true, Bottom: false})
+	table.SetCenterSeparator("|")
+	table.SetAutoWrapText(false)
+	table.SetHeader([]string{"case", "action", "class", "requests", "retried", "points", "sample"})
+	for _, r := range recs {
+		for _, e := range r.Errors {
+			sample := ""
+			if len(e.Samples) > 0 {
+				sample = e.Samples[0]
+			}
+			if len(sample) > 80 {
+				sample = sample[:80] + "..."
+			}
+			table.Append([]string{r.Case, r.Action, e.Class,
+				fmt.Sprintf("%d", e.Count), fmt.Sprintf("%d", e.Retried), fmt.Sprintf("%d", e.Points), sample})
+		}
+	}
+	table.Render()
+}
@@ -0,0 +1,223 @@
+package report
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"sort"
+	"time"
+)
+
+type htmlCase struct {
+	Name        string
+	Records     []Record
+	HasErrors   bool
+	Throughput  template.HTML // throughput over time, empty if no interval results
+	Latency     template.HTML // latency over time, empty if no interval results
+	Percentiles template.HTML
+}
+
+type htmlComparison struct {
+	Connections []string
+	Rows        []htmlComparisonRow
+}
+
+type htmlComparisonRow struct {
+	Workload string
+	Cells    []htmlComparisonCell
+}
+
+type htmlComparisonCell struct {
+	Found      bool
+	Best       bool // highest throughput of the row
+	Throughput uint64
+	P99        string
+	Failure    string
+}
+
+type htmlPage struct {
+	Result     Result
+	Duration   time.Duration
+	Cases      []htmlCase
+	Comparison htmlComparison
+}
+
+// RenderHTML render result as a self-contained html page with charts of every case
+// and comparison across connections. Intervals are keyed by case name, cases without
+// interval results have no over time charts.
+func RenderHTML(w io.Writer, res Result, intervals map[string][]Interval) error {
+	page := htmlPage{
+		Result:     res,
+		Duration:   res.Finish.Sub(res.Start).Round(time.Second),
+		Comparison: compareConnections(res.Records),
+	}
+	for _, name := range caseNames(res.Records) {
+		c := htmlCase{Name: name}
+		for _, r := range res.Records {
+			if r.Case == name {
+				c.Records = append(c.Records, r)
+			}
+		}
+		if ivs := intervals[name]; len(ivs) > 0 {
+			c.Throughput, c.Latency = intervalCharts(ivs)
+		}
+		c.Percentiles = percentilesChart(c.Records)
+		c.HasErrors = HasErrors(c.Records)
+		page.Cases = append(page.Cases, c)
+	}
+	return htmlTemplate.Execute(w, page)
+}
+
+// caseNames return case names in order of first record
+func caseNames(recs []Record) []string {
+	names := []string{}
+	seen := map[string]bool{}
+	for _, r := range recs {
+		if !seen[r.Case] {
+			seen[r.Case] = true
+			names = append(names, r.Case)
+		}
+	}
+	return names
+}
+
+// intervalCharts draw throughput and latency (p50, p99) over time, a line per operation type
+func intervalCharts(intervals []Interval) (template.HTML, template.HTML) {
+	ops := []string{}
+	byOp := map[string][]Interval{}
+	for _, iv := range intervals {
+		if _, ok := byOp[iv.Op]; !ok {
+			ops = append(ops, iv.Op)
+		}
+		byOp[iv.Op] = append(byOp[iv.Op], iv)
+	}
+	throughput, latency := []chartSeries{}, []chartSeries{}
+	for _, op := range ops {
+		tp := chartSeries{Name: op}
+		p50, p99 := chartSeries{Name: op + " p50"}, chartSeries{Name: op + " p99"}
+		for _, iv := range byOp[op] {
+			tp.X, tp.Y = append(tp.X, iv.ElapsedSec), append(tp.Y, iv.PPS)
+			// no latency without any request
+			if iv.Requests == 0 {
+				continue
+			}
+			p50.X, p50.Y = append(p50.X, iv.ElapsedSec), append(p50.Y, iv.LatP50Ms)
+			p99.X, p99.Y = append(p99.X, iv.ElapsedSec), append(p99.Y, iv.LatP99Ms)
+		}
+		throughput = append(throughput, tp)
+		latency = append(latency, p50, p99)
+	}
+	return lineChart(throughput, "seconds", fmtCount), lineChart(latency, "seconds", fmtMs)
+}
+
+// percentilesChart draw latency percentiles, a bar per operation type
+func percentilesChart(recs []Record) template.HTML {
+	series := []chartSeries{}
+	for _, r := range recs {
+		series = append(series, chartSeries{Name: r.Action, Y: []float64{r.LatP50Ms, r.LatP90Ms, r.LatP99Ms, r.LatP999Ms, r.LatMaxMs}})
+	}
+	return barChart([]string{"p50", "p90", "p99", "p999", "max"}, series, fmtMs)
+}
+
+// compareConnections put records of the same workload (action, concurrency, batch size and target rate)
+// side by side, a column per connection. The last record wins if a workload ran more than once on a connection.
+func compareConnections(recs []Record) htmlComparison {
+	cmp := htmlComparison{}
+	connIdx := map[string]int{}
+	rowIdx := map[string]int{}
+	for _, r := range recs {
+		if _, ok := connIdx[r.Connection]; !ok {
+			connIdx[r.Connection] = len(cmp.Connections)
+			cmp.Connections = append(cmp.Connections, r.Connection)
+		}
+	}
+	for _, r := range recs {
+		workload := fmt.Sprintf("%s, %d concurrent, batch %d", r.Action, r.Concurrent, r.BatchSize)
+		if r.TargetPPS > 0 {
+			workload += ", target " + fmtTarget(r.TargetPPS, r.OpenLoop)
+		}
+		i, ok := rowIdx[workload]
+		if !ok {
+			i = len(cmp.Rows)
+			rowIdx[workload] = i
+			cmp.Rows = append(cmp.Rows, htmlComparisonRow{Workload: workload, Cells: make([]htmlComparisonCell, len(cmp.Connections))})
+		}
+		cmp.Rows[i].Cells[connIdx[r.Connection]] = htmlComparisonCell{
+			Found:      true,
+			Throughput: r.Throughput,
+			P99:        fmtMs(r.LatP99Ms),
+			Failure:    fmt.Sprintf("%.2f%%", failureRate(r)),
+		}
+	}
+	for _, row := range cmp.Rows {
+		best := -1
+		for i, c := range row.Cells {
+			if c.Found && (best < 0 || c.Throughput > row.Cells[best].Throughput) {
+				best = i
+			}
+		}
+		if best >= 0 {
+			row.Cells[best].Best = true
+		}
+	}
+	sort.SliceStable(cmp.Rows, func(i, j int) bool { return cmp.Rows[i].Workload < cmp.Rows[j].Workload })
+	return cmp
+}
+
+var htmlTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"fmtMs":       fmtMs,
+	"fmtRun":      fmtRun,
+	"fmtTarget":   fmtTarget,
+	"fmtAchieved": fmtAchieved,
+	"fmtRetried":  fmtRetried,
+	"fmtTime":     func(t time.Time) string { return t.Local().Format("2006-01-02 15:04:05") },
+}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>dbstress report {{fmtTime .Result.Start}}</title>
+<style>
+body { font-family: sans-serif; font-size: 14px; margin: 24px; color: #222; }
+table { border-collapse: collapse; margin: 8px 0 16px; }
+th, td { border: 1px solid #ccc; padding: 4px 8px; text-align: right; }
+th { background: #f4f4f4; }
+td.left, th.left { text-align: left; }
+td.best { background: #e6f4e6; font-weight: bold; }
+.charts { display: flex; flex-wrap: wrap; gap: 8px; }
+.chart h4 { margin: 4px 0; }
+.meta { color: #666; }
+</style>
+</head>
+<body>
+<h1>dbstress report</h1>
+<p class="meta">
+started {{fmtTime .Result.Start}}, took {{.Duration}}{{if .Result.Version}}, dbstress {{.Result.Version}}{{end}}<br>
+point template: <code>{{.Result.Template}}</code>, fast({{.Result.Fast}}) tick({{.Result.Tick}})
+</p>
+
+<h2>Comparison across connections</h2>
+<table>
+<tr><th class="left">workload</th>{{range .Comparison.Connections}}<th>{{.}}</th>{{end}}</tr>
+{{range .Comparison.Rows}}<tr><td class="left">{{.Workload}}</td>{{range .Cells}}{{if .Found}}<td{{if .Best}} class="best"{{end}}>{{.Throughput}}/s<br>p99 {{.P99}}<br>failed {{.Failure}}</td>{{else}}<td>-</td>{{end}}{{end}}</tr>
+{{end}}</table>
+
+{{range .Cases}}
+<h2>{{.Name}}</h2>
+<table>
+<tr><th class="left">connection</th><th class="left">action</th><th>concur</th><th>batch</th><th>run</th><th>throughput</th><th>target</th><th>achieved</th><th>points</th><th>first try</th><th>retried</th><th>failed</th><th>timeouts</th><th>p50</th><th>p90</th><th>p99</th><th>p999</th><th>max</th></tr>
+{{range .Records}}<tr><td class="left">{{.Connection}}</td><td class="left">{{.Action}}</td><td>{{.Concurrent}}</td><td>{{.BatchSize}}</td><td><span{{if .Failure}} title="{{.Failure}}"{{end}}>{{fmtRun .RuntimeSec .Interrupted .Failure}}</span></td><td>{{.Throughput}}</td><td>{{fmtTarget .TargetPPS .OpenLoop}}</td><td>{{fmtAchieved .AchievedPPS .TargetPPS}}</td><td>{{.Points}}</td><td>{{.FirstTry}}</td><td>{{fmtRetried .Retried .Retries}}</td><td>{{.Failed}}</td><td>{{.Timeouts}}</td><td>{{fmtMs .LatP50Ms}}</td><td>{{fmtMs .LatP90Ms}}</td><td>{{fmtMs .LatP99Ms}}</td><td>{{fmtMs .LatP999Ms}}</td><td>{{fmtMs .LatMaxMs}}</td></tr>
+{{end}}</table>
+{{if .HasErrors}}<h4>errors</h4>
+<table>
+<tr><th class="left">action</th><th class="left">class</th><th>requests</th><th>retried</th><th>points</th><th class="left">samples</th></tr>
+{{range .Records}}{{$action := .Action}}{{range .Errors}}<tr><td class="left">{{$action}}</td><td class="left">{{.Class}}</td><td>{{.Count}}</td><td>{{.Retried}}</td><td>{{.Points}}</td><td class="left">{{range .Samples}}<code>{{.}}</code><br>{{end}}</td></tr>
+{{end}}{{end}}</table>{{end}}
+<div class="charts">
+{{if .Throughput}}<div class="chart"><h4>throughput (points or queries per second)</h4>{{.Throughput}}</div>
+<div class="chart"><h4>latency over time</h4>{{.Latency}}</div>{{end}}
+<div class="chart"><h4>latency percentiles</h4>{{.Percentiles}}</div>
+</div>
+{{end}}
+</body>
+</html>
+`))
@@ -0,0 +1,74 @@
+package report
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestCompareConnections(t *testing.T) {
+	recs := []Record{
+		{Case: "influx-w", Connection: "influx", Action: "insert", Concurrent: 4, BatchSize: 5000, Throughput: 300000, Points: 1000, Failed: 10},
+		{Case: "mysql-w", Connection: "mysql", Action: "insert", Concurrent: 4, BatchSize: 5000, Throughput: 100000},
+		{Case: "influx-q", Connection: "influx", Action: "query", Concurrent: 2, Throughput: 50},
+	}
+	cmp := compareConnections(recs)
+	if got, exp := strings.Join(cmp.Connections, ","), "influx,mysql"; got != exp {
+		t.Fatalf("Wrong connections. Got %v, Expected: %v\n", got, exp)
+	}
+	if got, exp := len(cmp.Rows), 2; got != exp {
+		t.Fatalf("Wrong number of rows. Got %v, Expected: %v\n", got, exp)
+	}
+
+	insert := cmp.Rows[0]
+	if !insert.Cells[0].Best || insert.Cells[1].Best || !insert.Cells[1].Found {
+		t.Errorf("Wrong insert row %+v\n", insert)
+	}
+	if got, exp := insert.Cells[0].Failure, "1.00%"; got != exp {
+		t.Errorf("Wrong failure rate. Got %v, Expected: %v\n", got, exp)
+	}
+	query := cmp.Rows[1]
+	if !query.Cells[0].Found || query.Cells[1].Found {
+		t.Errorf("Wrong query row %+v\n", query)
+	}
+}
+
+func TestRenderHTML(t *testing.T) {
+	res := Result{Records: []Record{
+		{Case: "<case>", Connection: "influx", Action: "insert", Throughput: 1000, LatP99Ms: 12,
+			Errors: []ErrorClass{{Class: "http 503", Count: 2, Points: 2000, Samples: []string{"<busy>"}}}},
+	}}
+	intervals := map[string][]Interval{
+		"<case>": {
+			{Op: "insert", ElapsedSec: 0, Requests: 10, PPS: 1000, LatP99Ms: 10},
+			{Op: "insert", ElapsedSec: 1, PPS: 0},
+		},
+	}
+	buf := &bytes.Buffer{}
+	if err := RenderHTML(buf, res, intervals); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	if got, exp := strings.Count(out, "<svg"), 3; got != exp {
+		t.Errorf("Wrong number of charts. Got %v, Expected: %v\n", got, exp)
+	}
+	if strings.Contains(out, "<case>") || !strings.Contains(out, "&lt;case&gt;") {
+		t.Errorf("Case name should be escaped")
+	}
+	if !strings.Contains(out, "http 503") || !strings.Contains(out, "&lt;busy&gt;") {
+		t.Errorf("Errors of case should be rendered with escaped samples")
+	}
+}
+
+func TestNiceCeil(t *testing.T) {
+	tests := []struct {
+		v, exp float64
+	}{
+		{0, 1}, {0.3, 0.5}, {1, 1}, {13, 20}, {420, 500}, {5000, 5000}, {7100, 10000},
+	}
+	for _, tt := range tests {
+		if got := niceCeil(tt.v); got != tt.exp {
+			t.Errorf("Wrong nice ceil of %v. Got %v, Expected: %v\n", tt.v, got, tt.exp)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+
+	"github.com/deltacat/dbstress/csv"
+)
+
+// Interval results of an operation type in a case completed within an interval
+type Interval struct {
+	Op         string    `json:"op" csv:"op"`
+	Start      time.Time `json:"start" csv:"start"`
+	ElapsedSec float64   `json:"elapsed_sec" csv:"elapsed_sec"` // since case start
+	Requests   uint64    `json:"requests" csv:"requests"`
+	Points     uint64    `json:"points" csv:"points"`
+	Failed     uint64    `json:"failed" csv:"failed"`
+	PPS        float64   `json:"pps" csv:"pps"` // points written per second
+	LatMeanMs  float64   `json:"lat_mean_ms" csv:"lat_mean_ms"`
+	LatP50Ms   float64   `json:"lat_p50_ms" csv:"lat_p50_ms"`
+	LatP90Ms   float64   `json:"lat_p90_ms" csv:"lat_p90_ms"`
+	LatP99Ms   float64   `json:"lat_p99_ms" csv:"lat_p99_ms"`
+	LatMaxMs   float64   `json:"lat_max_ms" csv:"lat_max_ms"`
+}
+
+var unsafeFileChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// IntervalFileName return interval results file name of a case started at given time, format is csv or json
+func IntervalFileName(dir, caseName string, start time.Time, format string) string {
+	name := unsafeFileChars.ReplaceAllString(caseName, "_")
+	return filepath.Join(dir, name+"-"+start.Local().Format("20060102-150405")+"."+format)
+}
+
+// SaveIntervals save interval results of a case, format is csv or json
+func SaveIntervals(filename, format string, intervals []Interval) error {
+	if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+		return err
+	}
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	switch format {
+	case FormatCSV:
+		err = csv.Marshal(f, &intervals)
+	case FormatJSON:
+		enc := json.NewEncoder(f)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(intervals)
+	default:
+		err = fmt.Errorf("unknown intervals format %q, expect csv or json", format)
+	}
+	if err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// LoadIntervals load interval results of a case saved as csv or json, format is guessed from extension
+func LoadIntervals(filename string) ([]Interval, error) {
+	intervals := []Interval{}
+	if FormatFromExt(filename) == FormatCSV {
+		err := csv.Parse(filename, &intervals)
+		return intervals, err
+	}
+	b, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return intervals, err
+	}
+	err = json.Unmarshal(b, &intervals)
+	return intervals, err
+}
+
+// LoadResultIntervals load interval results of every case of result, keyed by case name.
+// Intervals files are looked up as saved, then relative to dir of the result file.
+func LoadResultIntervals(res Result, resultFile string) (map[string][]Interval, []error) {
+	intervals := map[string][]Interval{}
+	errs := []error{}
+	for _, r := range res.Records {
+		if r.IntervalsFile == "" {
+			continue
+		}
+		if _, ok := intervals[r.Case]; ok {
+			continue
+		}
+		filename := r.IntervalsFile
+		if _, err := os.Stat(filename); os.IsNotExist(err) && !filepath.IsAbs(filename) {
+			filename = filepath.Join(filepath.Dir(resultFile), filepath.Base(filepath.Dir(filename)), filepath.Base(filename))
+		}
+		ivs, err := LoadIntervals(filename)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("case %q: %v", r.Case, err))
+			continue
+		}
+		intervals[r.Case] = ivs
+	}
+	return intervals, errs
+}
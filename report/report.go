@@ -1,30 +1,228 @@
 package report
 
 import (
+	"encoding/json"
+	"fmt"
+	"io"
 	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
 
+	"github.com/deltacat/dbstress/csv"
 	"github.com/olekukonko/tablewriter"
 )
 
-var table *tablewriter.Table
+// supported report formats
+const (
+	FormatTable    = "table"
+	FormatJSON     = "json"
+	FormatCSV      = "csv"
+	FormatMarkdown = "markdown"
+)
+
+// Record result of an operation type in a case
+type Record struct {
+	Case        string    `json:"case" csv:"case"`
+	Connection  string    `json:"connection" csv:"connection"`
+	Action      string    `json:"action" csv:"action"`
+	Concurrent  int       `json:"concurrent" csv:"concurrent"`
+	BatchSize   int       `json:"batch_size" csv:"batch_size"`
+	Gzip        int       `json:"gzip" csv:"gzip"`
+	Template    string    `json:"template" csv:"template"`
+	Start       time.Time `json:"start" csv:"start"`
+	RuntimeSec  float64   `json:"runtime_sec" csv:"runtime_sec"`
+	Throughput  uint64    `json:"throughput" csv:"throughput"`
+	TargetPPS   uint64    `json:"target_pps" csv:"target_pps"`
+	OpenLoop    bool      `json:"open_loop" csv:"open_loop"`       // latency measured from intended send time
+	Interrupted bool      `json:"interrupted" csv:"interrupted"`   // case stopped before finished, e.g. by ctrl-c
+	Failure     string    `json:"failure,omitempty" csv:"failure"` // why the case failed, e.g. failure budget exceeded
+	AchievedPPS float64   `json:"achieved_pps" csv:"achieved_pps"`
+	Points      uint64    `json:"points" csv:"points"`
+	FirstTry    uint64    `json:"first_try" csv:"first_try"` // points written by the first try
+	Retried     uint64    `json:"retried" csv:"retried"`     // points written after retries
+	Retries     uint64    `json:"retries" csv:"retries"`     // retries sent
+	Failed      uint64    `json:"failed" csv:"failed"`       // points failed after all attempts
+	Timeouts    uint64    `json:"timeouts" csv:"timeouts"`   // requests not answered within timeout of the connection
+	Dropped     uint64    `json:"dropped" csv:"dropped"`     // results of the whole case missing from latency statistics as sinks could not keep up, on the first row of the case only
+	LatMeanMs   float64   `json:"lat_mean_ms" csv:"lat_mean_ms"`
+	LatStdDevMs float64   `json:"lat_stddev_ms" csv:"lat_stddev_ms"`
+	LatP50Ms    float64   `json:"lat_p50_ms" csv:"lat_p50_ms"`
+	LatP90Ms    float64   `json:"lat_p90_ms" csv:"lat_p90_ms"`
+	LatP99Ms    float64   `json:"lat_p99_ms" csv:"lat_p99_ms"`
+	LatP999Ms   float64   `json:"lat_p999_ms" csv:"lat_p999_ms"`
+	LatMaxMs    float64   `json:"lat_max_ms" csv:"lat_max_ms"`
 
-// SetHeader set report table header row
-func SetHeader(keys []string) {
-	table.SetHeader(keys)
+	IntervalsFile string       `json:"intervals_file,omitempty" csv:"intervals_file"` // per-interval results of the case
+	Errors        []ErrorClass `json:"errors,omitempty" csv:"-"`                      // failed requests by class
 }
 
-// Append append row to report
-func Append(row []string) {
-	table.Append(row)
+var (
+	mu      sync.Mutex
+	records []Record
+)
+
+// Append append record to report
+func Append(rec Record) {
+	mu.Lock()
+	records = append(records, rec)
+	mu.Unlock()
 }
 
-// Render render report
-func Render() {
-	table.Render()
+// Records return all appended records
+func Records() []Record {
+	mu.Lock()
+	defer mu.Unlock()
+	return append([]Record{}, records...)
+}
+
+// IsValidFormat check if format is supported
+func IsValidFormat(format string) bool {
+	switch format {
+	case FormatTable, FormatJSON, FormatCSV, FormatMarkdown:
+		return true
+	}
+	return false
+}
+
+// Render render report in given format
+func Render(w io.Writer, format string) error {
+	recs := Records()
+	switch format {
+	case FormatTable, "":
+		renderTable(w, recs, false)
+	case FormatMarkdown:
+		renderTable(w, recs, true)
+	case FormatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(recs)
+	case FormatCSV:
+		return csv.Marshal(w, &recs)
+	default:
+		return fmt.Errorf("unknown report format %q", format)
+	}
+	return nil
+}
+
+// RenderFile render report to file, in format of file extension if recognised, otherwise in given format
+func RenderFile(filename, format string) error {
+	if f := FormatFromExt(filename); f != "" {
+		format = f
+	}
+	if dir := filepath.Dir(filename); dir != "" {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return err
+		}
+	}
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	if err := Render(f, format); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// FormatFromExt guess report format from file extension, empty if not recognised
+func FormatFromExt(filename string) string {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".json":
+		return FormatJSON
+	case ".csv":
+		return FormatCSV
+	case ".md", ".markdown":
+		return FormatMarkdown
+	case ".txt":
+		return FormatTable
+	}
+	return ""
 }
 
-func init() {
-	table = tablewriter.NewWriter(os.Stdout)
+func renderTable(w io.Writer, recs []Record, markdown bool) {
+	table := tablewriter.NewWriter(w)
 	table.SetBorders(tablewriter.Border{Left: true, Top: false, Right: true, Bottom: false})
 	table.SetCenterSeparator("|")
+	if markdown {
+		table.SetAutoFormatHeaders(false)
+		table.SetAutoWrapText(false)
+	}
+	table.SetHeader([]string{"case", "connection", "action", "concur", "batch", "gzip", "start", "run", "throughput", "target", "achieved", "points", "first try", "retried", "failed", "timeouts",
+		"mean", "stddev", "p50", "p90", "p99", "p999", "max"})
+	for _, r := range recs {
+		table.Append([]string{
+			r.Case,
+			r.Connection,
+			r.Action,
+			fmt.Sprintf("%d", r.Concurrent),
+			fmt.Sprintf("%d", r.BatchSize),
+			fmt.Sprintf("%d", r.Gzip),
+			r.Start.Local().Format("2006-01-02 15:04:05"),
+			fmtRun(r.RuntimeSec, r.Interrupted, r.Failure),
+			fmt.Sprintf("%d", r.Throughput),
+			fmtTarget(r.TargetPPS, r.OpenLoop),
+			fmtAchieved(r.AchievedPPS, r.TargetPPS),
+			fmt.Sprintf("%d", r.Points),
+			fmt.Sprintf("%d", r.FirstTry),
+			fmtRetried(r.Retried, r.Retries),
+			fmt.Sprintf("%d", r.Failed),
+			fmt.Sprintf("%d", r.Timeouts),
+			fmtMs(r.LatMeanMs),
+			fmtMs(r.LatStdDevMs),
+			fmtMs(r.LatP50Ms),
+			fmtMs(r.LatP90Ms),
+			fmtMs(r.LatP99Ms),
+			fmtMs(r.LatP999Ms),
+			fmtMs(r.LatMaxMs)})
+	}
+	table.Render()
+}
+
+// fmtRun format runtime, marked if failed or interrupted
+func fmtRun(sec float64, interrupted bool, failure string) string {
+	switch {
+	case failure != "":
+		return fmt.Sprintf("%.0fs failed", sec)
+	case interrupted:
+		return fmt.Sprintf("%.0fs interrupted", sec)
+	}
+	return fmt.Sprintf("%.0fs", sec)
+}
+
+// fmtRetried format points written after retries, with retries sent if any
+func fmtRetried(retried, retries uint64) string {
+	if retries == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%d (%d retries)", retried, retries)
+}
+
+func fmtTarget(pps uint64, openLoop bool) string {
+	if pps == 0 {
+		return "-"
+	}
+	if openLoop {
+		return fmt.Sprintf("%d open", pps)
+	}
+	return fmt.Sprintf("%d", pps)
+}
+
+// fmtAchieved format achieved rate, with percent of target if any
+func fmtAchieved(achieved float64, target uint64) string {
+	if target == 0 {
+		return fmt.Sprintf("%.0f", achieved)
+	}
+	return fmt.Sprintf("%.0f (%.1f%%)", achieved, achieved/float64(target)*100)
+}
+
+func fmtMs(ms float64) string {
+	return time.Duration(ms * float64(time.Millisecond)).Round(10 * time.Microsecond).String()
+}
+
+// DurationMs convert duration to milliseconds
+func DurationMs(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
 }
@@ -0,0 +1,91 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// setRecords replace appended records for test, return func restoring them
+func setRecords(recs []Record) func() {
+	mu.Lock()
+	prev := records
+	records = recs
+	mu.Unlock()
+	return func() {
+		mu.Lock()
+		records = prev
+		mu.Unlock()
+	}
+}
+
+func TestRender(t *testing.T) {
+	defer setRecords([]Record{{Case: "influx-w", Connection: "influx", Action: "insert", Throughput: 1000, Points: 5000}})()
+
+	tests := []struct {
+		format   string
+		contains string
+	}{
+		{FormatJSON, `"case": "influx-w"`},
+		{FormatCSV, "case,connection,action"},
+		{FormatMarkdown, "| influx-w |"},
+		{FormatTable, "| influx-w |"},
+	}
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		if err := Render(&buf, tt.format); err != nil {
+			t.Errorf("Render %s failed: %v\n", tt.format, err)
+			continue
+		}
+		if !strings.Contains(buf.String(), tt.contains) {
+			t.Errorf("Wrong %s report, %q not found in:\n%s\n", tt.format, tt.contains, buf.String())
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := Render(&buf, FormatJSON); err == nil {
+		var recs []Record
+		if err := json.Unmarshal(buf.Bytes(), &recs); err != nil || len(recs) != 1 || recs[0].Points != 5000 {
+			t.Errorf("Wrong json report. Got %+v, %v\n", recs, err)
+		}
+	}
+	if err := Render(&buf, "xml"); err == nil {
+		t.Errorf("Unknown format should fail\n")
+	}
+}
+
+func TestRenderFile(t *testing.T) {
+	defer setRecords([]Record{{Case: "influx-w", Connection: "influx", Action: "insert"}})()
+	dir, err := ioutil.TempDir("", "report")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	tests := []struct {
+		file, format string
+		prefix       string
+	}{
+		{"report.json", FormatMarkdown, "["},                  // extension wins
+		{"report.csv", FormatTable, "case,"},                  // extension wins
+		{"report.md", FormatJSON, "|"},                        // extension wins
+		{"report.out", FormatJSON, "["},                       // unknown extension, format given
+		{"report", FormatCSV, "case,"},                        // no extension, format given
+		{filepath.Join("sub", "report.JSON"), FormatCSV, "["}, // extension case ignored, dir created
+	}
+	for _, tt := range tests {
+		file := filepath.Join(dir, tt.file)
+		if err := RenderFile(file, tt.format); err != nil {
+			t.Errorf("RenderFile %s failed: %v\n", tt.file, err)
+			continue
+		}
+		b, _ := ioutil.ReadFile(file)
+		if !strings.HasPrefix(strings.TrimSpace(string(b)), tt.prefix) {
+			t.Errorf("Wrong format of %s with %s. Got:\n%s\n", tt.file, tt.format, b)
+		}
+	}
+}
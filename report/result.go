@@ -0,0 +1,64 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// Result structured result of a cases run, saved at the end of every run
+type Result struct {
+	Version  string    `json:"version"`
+	Start    time.Time `json:"start"`
+	Finish   time.Time `json:"finish"`
+	Template string    `json:"template"`
+	Fast     bool      `json:"fast"`
+	Tick     string    `json:"tick"`
+	Records  []Record  `json:"records"`
+}
+
+// ResultFileName return result file name of a run started at given time
+func ResultFileName(dir string, start time.Time) string {
+	return filepath.Join(dir, "dbstress-"+start.Local().Format("20060102-150405")+".json")
+}
+
+// SaveResult save result as json file
+func SaveResult(filename string, res Result) error {
+	if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+		return err
+	}
+	b, err := json.MarshalIndent(res, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filename, b, 0644)
+}
+
+// LoadResult load result from json file
+func LoadResult(filename string) (Result, error) {
+	res := Result{}
+	b, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return res, err
+	}
+	err = json.Unmarshal(b, &res)
+	return res, err
+}
+
+// LatestResultFile return the latest result file saved in dir
+func LatestResultFile(dir string) (string, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "dbstress-*.json"))
+	if err != nil {
+		return "", err
+	}
+	if len(files) == 0 {
+		return "", fmt.Errorf("no result file found in %q", dir)
+	}
+	// named by start time, the latest sorts last
+	sort.Strings(files)
+	return files[len(files)-1], nil
+}
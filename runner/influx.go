@@ -1,11 +1,12 @@
 package runner
 
 import (
+	"context"
 	"sync"
 	"sync/atomic"
-	"time"
 
 	"github.com/deltacat/dbstress/client"
+	"github.com/deltacat/dbstress/config"
 	"github.com/deltacat/dbstress/data/influx/lineprotocol"
 	"github.com/deltacat/dbstress/data/influx/point"
 	"github.com/deltacat/dbstress/stress"
@@ -14,6 +15,7 @@ import (
 // InfluxRunner influxdb runner
 type InfluxRunner struct {
 	caseRunner
+	precision lineprotocol.Precision
 }
 
 // NewInfluxRunner create a new mysql runner instance
@@ -22,13 +24,47 @@ func NewInfluxRunner(cli client.Client, cs CaseConfig) InfluxRunner {
 		caseRunner: caseRunner{
 			cli:         cli,
 			cfg:         cs,
+			points:      cs.pointsConfig(),
 			concurrency: cs.Concurrent,
 		},
 	}
 }
 
+func init() {
+	RegisterFactory(config.DriverInfluxDB, buildInfluxRunner)
+}
+
+// buildInfluxRunner build runner of a case on influxdb connection
+func buildInfluxRunner(cfg config.Config, cf CaseConfig) (Runner, error) {
+	pc := cf.pointsConfig()
+	cof, err := cfg.FindInfluxDBConnection(cf.Connection)
+	if err != nil {
+		return nil, err
+	}
+	if cf.Precision != "" {
+		cof.Precision = cf.Precision
+	}
+	precision, err := lineprotocol.ParsePrecision(cof.Precision)
+	if err != nil {
+		return nil, err
+	}
+	cli, err := client.NewInfluxClient(cof, "")
+	if err != nil {
+		return nil, err
+	}
+	r := NewInfluxRunner(cli, cf)
+	r.precision = precision
+	if cf.HasQueries() {
+		if r.queries, err = newInfluxQueryGenerator(pc, cof.APIVersion, cof.V2.Bucket); err != nil {
+			cli.Close()
+			return nil, err
+		}
+	}
+	return &r, nil
+}
+
 // Run run the case
-func (r *InfluxRunner) Run() error {
+func (r *InfluxRunner) Run(ctx context.Context) error {
 	defer r.cli.Close()
 	if !kapacitorMode {
 		if err := r.cli.Create(""); err != nil {
@@ -36,36 +72,29 @@ func (r *InfluxRunner) Run() error {
 		}
 	}
 
-	return r.doInsert(r.doWriteInflux)
+	return r.run(ctx, ActionInsert, r.doWriteInflux)
 }
 
-func (r *InfluxRunner) doWriteInflux(resultChan chan stress.WriteResult) (uint64, uint64, error) {
+func (r *InfluxRunner) doWriteInflux(ctx context.Context, resultChan chan stress.WriteResult, workers int) (uint64, uint64, error) {
 	var wg sync.WaitGroup
-	wg.Add(r.concurrency)
+	wg.Add(workers)
 
-	seriesN := pointsCfg.SeriesN
+	seriesN := r.points.SeriesN
 
 	var totalWritten uint64
 	var totalFailed uint64
 	startSplit := 0
-	inc := int(seriesN) / int(r.concurrency)
+	inc := int(seriesN) / workers
 	endSplit := inc
 
-	pts := point.NewPoints(pointsCfg.Measurement, pointsCfg.SeriesKey, pointsCfg.FieldsStr, seriesN, lineprotocol.Nanosecond)
-	for i := uint64(0); i < uint64(r.concurrency); i++ {
+	pts := point.NewPoints(r.points.Measurement, r.points.SeriesKey, r.points.FieldsStr, seriesN, r.precision)
+	for i := 0; i < workers; i++ {
 
 		go func(startSplit, endSplit int) {
-			cfg := stress.WriteConfig{
-				BatchSize: uint64(r.cfg.BatchSize),
-				MaxPoints: pointsN / uint64(r.concurrency), // divide by concurreny
-				GzipLevel: r.cfg.Gzip,
-				Deadline:  time.Now().Add(r.cfg.Runtime.Duration),
-				Tick:      time.Tick(tick),
-				Results:   resultChan,
-			}
+			cfg := r.newWriteConfig(resultChan, workers, r.limiter)
 
 			// Ignore duration from a single call to Write.
-			pointsWritten, pointsFailed, _ := stress.WriteInflux(pts[startSplit:endSplit], r.cli, cfg)
+			pointsWritten, pointsFailed, _ := stress.WriteInflux(ctx, pts[startSplit:endSplit], r.cli, cfg)
 			atomic.AddUint64(&totalWritten, pointsWritten)
 			atomic.AddUint64(&totalFailed, pointsFailed)
 
@@ -0,0 +1,40 @@
+package runner
+
+import (
+	"fmt"
+
+	"github.com/deltacat/dbstress/client"
+	"github.com/deltacat/dbstress/config"
+	"github.com/deltacat/dbstress/data/influx/lineprotocol"
+	"github.com/deltacat/dbstress/utils"
+)
+
+func init() {
+	RegisterFactory(config.DriverLineProtocol, buildLineProtocolRunner)
+}
+
+// buildLineProtocolRunner build runner of a case on generic line protocol connection,
+// points are written the same way as influxdb, queries are not supported
+func buildLineProtocolRunner(cfg config.Config, cf CaseConfig) (Runner, error) {
+	if cf.HasQueries() {
+		return nil, fmt.Errorf("queries of case %q on lineprotocol connection: %w", cf.Name, utils.ErrNotSupport)
+	}
+	cof, err := cfg.FindLineProtocolConnection(cf.Connection)
+	if err != nil {
+		return nil, err
+	}
+	if cf.Precision != "" {
+		cof.Precision = cf.Precision
+	}
+	precision, err := lineprotocol.ParsePrecision(cof.Precision)
+	if err != nil {
+		return nil, err
+	}
+	cli, err := client.NewLineProtocolClient(cof)
+	if err != nil {
+		return nil, err
+	}
+	r := NewInfluxRunner(cli, cf)
+	r.precision = precision
+	return &r, nil
+}
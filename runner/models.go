@@ -1,13 +1,122 @@
 package runner
 
-import "github.com/deltacat/dbstress/csv"
+import (
+	"time"
+
+	"github.com/deltacat/dbstress/config"
+	"github.com/deltacat/dbstress/csv"
+	"github.com/deltacat/dbstress/stress"
+)
+
+// case actions
+const (
+	ActionInsert = stress.OpInsert
+	ActionCopy   = stress.OpCopy
+	ActionQuery  = stress.OpQuery
+	ActionMixed  = "mixed" // writes and queries at the same time, split workers by read-percent
+)
 
 // CaseConfig test case config
 type CaseConfig struct {
-	Name       string       `mapstructure:"name"`
-	Connection string       `mapstructure:"connection"`
-	Concurrent int          `mapstructure:"concurrent"`
-	BatchSize  int          `mapstructure:"batch-size"`
-	Gzip       int          `mapstructure:"gzip"` // If non-zero, gzip write bodies with given compression level. 1=best speed, 9=best compression, -1=gzip default.
-	Runtime    csv.Duration `mapstructure:"runtime"`
+	Name        string       `mapstructure:"name"`
+	Connection  string       `mapstructure:"connection"`
+	Driver      string       `mapstructure:"driver"` // influxdb, lineprotocol, mysql or postgres, the default connection of it is used if no connection is named
+	Concurrent  int          `mapstructure:"concurrent"`
+	BatchSize   int          `mapstructure:"batch-size"`
+	Gzip        int          `mapstructure:"gzip"` // If non-zero, gzip write bodies with given compression level. 1=best speed, 9=best compression, -1=gzip default.
+	Runtime     csv.Duration `mapstructure:"runtime"`
+	Action      string       `mapstructure:"action"`       // insert (default), query or mixed
+	ReadPercent int          `mapstructure:"read-percent"` // Percent of workers running queries in mixed case
+	PPS         uint64       `mapstructure:"pps"`          // Target points per second of all write workers, 0 means unlimited
+	Profile     string       `mapstructure:"profile"`      // Load profile overrides pps, e.g. ramp:1000-50000, steps:10000@30s,20000@30s, spike:10000-50000@1m/5s
+	OpenLoop    bool         `mapstructure:"open-loop"`    // Schedule writes on a fixed timeline of pps or profile, measure latency from intended send time
+
+	RetryAttempts   int          `mapstructure:"retry-attempts"`    // Attempts of a batch answered 429, 503 or timed out, including the first one, 0 means no retry
+	RetryBackoff    csv.Duration `mapstructure:"retry-backoff"`     // Delay before the first retry, doubled on every retry, default 100ms
+	RetryMaxBackoff csv.Duration `mapstructure:"retry-max-backoff"` // Cap of the delay and of Retry-After asked by server, default 10s
+
+	MaxErrors     uint64  `mapstructure:"max-errors"`      // Fail the case once failed requests exceed the count, 0 means no limit
+	MaxErrorRatio float64 `mapstructure:"max-error-ratio"` // Fail the case once failed requests exceed the ratio (checked after 100 requests), 0 means no limit
+	Strict        bool    `mapstructure:"strict"`          // Fail the case at the first failed request, set for all cases by --strict
+
+	// overrides of global settings, only available in toml/yaml cases
+	Points    config.PointsConfig `mapstructure:"points" csv:"-"`    // Overrides fields of [points] which are set
+	Fast      *bool               `mapstructure:"fast" csv:"-"`      // Overrides cases.fast
+	Tick      time.Duration       `mapstructure:"tick" csv:"-"`      // Overrides cases.tick
+	Precision string              `mapstructure:"precision" csv:"-"` // Overrides precision of influxdb connection
+}
+
+// default backoff of retries
+const (
+	DefaultRetryBackoff    = 100 * time.Millisecond
+	DefaultRetryMaxBackoff = 10 * time.Second
+)
+
+// HasQueries return if the case runs queries
+func (c CaseConfig) HasQueries() bool {
+	return c.Action == ActionQuery || c.Action == ActionMixed
+}
+
+// pointsConfig return points config of the case, fields not set by the case fall back to [points]
+func (c CaseConfig) pointsConfig() config.PointsConfig {
+	p := pointsCfg
+	if c.Points.Measurement != "" {
+		p.Measurement = c.Points.Measurement
+	}
+	if c.Points.SeriesKey != "" {
+		p.SeriesKey = c.Points.SeriesKey
+	}
+	if c.Points.FieldsStr != "" {
+		p.FieldsStr = c.Points.FieldsStr
+	}
+	if c.Points.SeriesN > 0 {
+		p.SeriesN = c.Points.SeriesN
+	}
+	if c.Points.PointsN > 0 {
+		p.PointsN = c.Points.PointsN
+	}
+	return p
+}
+
+// retryPolicy return retry policy of writes of the case, nil if failed batches are not retried
+func (c CaseConfig) retryPolicy() *stress.RetryPolicy {
+	if c.RetryAttempts < 2 {
+		return nil
+	}
+	p := &stress.RetryPolicy{
+		MaxAttempts: c.RetryAttempts,
+		Backoff:     c.RetryBackoff.Duration,
+		MaxBackoff:  c.RetryMaxBackoff.Duration,
+	}
+	if p.Backoff == 0 {
+		p.Backoff = DefaultRetryBackoff
+	}
+	if p.MaxBackoff == 0 {
+		p.MaxBackoff = DefaultRetryMaxBackoff
+	}
+	return p
+}
+
+// failureBudget return failure budget of the case, strict if set by the case or globally
+func (c CaseConfig) failureBudget() stress.FailureBudget {
+	return stress.FailureBudget{
+		MaxErrors:     c.MaxErrors,
+		MaxErrorRatio: c.MaxErrorRatio,
+		Strict:        c.Strict || strict,
+	}
+}
+
+// batchTick return interval between batches of a worker, fast mode of the case or global wins over tick
+func (c CaseConfig) batchTick() time.Duration {
+	isFast := fast
+	if c.Fast != nil {
+		isFast = *c.Fast
+	}
+	if isFast {
+		return time.Nanosecond
+	}
+	if c.Tick > 0 {
+		return c.Tick
+	}
+	return tick
 }
@@ -1,12 +1,14 @@
 package runner
 
 import (
+	"context"
 	"sync"
 	"sync/atomic"
-	"time"
 
 	"github.com/deltacat/dbstress/client"
+	"github.com/deltacat/dbstress/config"
 	"github.com/deltacat/dbstress/data/mysql"
+	"github.com/deltacat/dbstress/data/query"
 	"github.com/deltacat/dbstress/stress"
 )
 
@@ -22,49 +24,74 @@ func NewMySQLRunner(cli client.Client, cs CaseConfig, layout mysql.Layout) MySQL
 		caseRunner: caseRunner{
 			cli:         cli,
 			cfg:         cs,
+			points:      cs.pointsConfig(),
 			concurrency: cs.Concurrent,
 		},
 		layout: layout,
 	}
 }
 
+func init() {
+	RegisterFactory(config.DriverMySQL, buildMySQLRunner)
+}
+
+// buildMySQLRunner build runner of a case on mysql connection
+func buildMySQLRunner(cfg config.Config, cf CaseConfig) (Runner, error) {
+	pc := cf.pointsConfig()
+	cof, err := cfg.FindMySQLConnection(cf.Connection)
+	if err != nil {
+		return nil, err
+	}
+	cli, err := client.NewMySQLClient(cof)
+	if err != nil {
+		return nil, err
+	}
+	layout, err := mysql.GenerateLayout(pc.Measurement, pc.SeriesKey, pc.FieldsStr)
+	if err != nil {
+		cli.Close()
+		return nil, err
+	}
+	r := NewMySQLRunner(cli, cf, layout)
+	if cf.HasQueries() {
+		if r.queries, err = newSQLQueryGenerator(pc, query.MySQL, mysql.TagValueCardinality); err != nil {
+			cli.Close()
+			return nil, err
+		}
+	}
+	return &r, nil
+}
+
 // Run run the case
-func (r *MySQLRunner) Run() error {
+func (r *MySQLRunner) Run(ctx context.Context) error {
 	if err := r.cli.Create(r.layout.GetCreateStmt()); err != nil {
 		return err
 	}
 
-	return r.doInsert(r.doWriteMysql)
+	return r.run(ctx, ActionInsert, r.doWriteMysql)
 }
 
-func (r *MySQLRunner) doWriteMysql(resultChan chan stress.WriteResult) (uint64, uint64, error) {
+func (r *MySQLRunner) doWriteMysql(ctx context.Context, resultChan chan stress.WriteResult, workers int) (uint64, uint64, error) {
 
 	var wg sync.WaitGroup
-	wg.Add(int(r.concurrency))
+	wg.Add(workers)
 
-	seriesN := pointsCfg.SeriesN
+	seriesN := r.points.SeriesN
 
 	totalWritten := uint64(0)
 	totalFailed := uint64(0)
 	startSplit := 0
-	inc := int(seriesN) / int(r.concurrency)
+	inc := int(seriesN) / workers
 	endSplit := inc
 
-	for i := uint64(0); i < uint64(r.concurrency); i++ {
+	for i := 0; i < workers; i++ {
 
 		go func(startSplit, endSplit int) {
 			tbl := mysql.NewTableChunk(r.layout, uint64(r.cfg.BatchSize))
 
-			cfg := stress.WriteConfig{
-				BatchSize: uint64(r.cfg.BatchSize),
-				MaxPoints: pointsN / uint64(r.concurrency), // divide by concurreny
-				Deadline:  time.Now().Add(r.cfg.Runtime.Duration),
-				Tick:      time.Tick(tick),
-				Results:   resultChan,
-			}
+			cfg := r.newWriteConfig(resultChan, workers, r.limiter)
 
 			// Ignore duration from a single call to Write.
-			pointsWritten, pointsFailed, _ := stress.WriteMySQL(tbl, r.cli, cfg)
+			pointsWritten, pointsFailed, _ := stress.WriteMySQL(ctx, tbl, r.cli, cfg)
 			atomic.AddUint64(&totalWritten, pointsWritten)
 			atomic.AddUint64(&totalFailed, pointsFailed)
 
@@ -0,0 +1,104 @@
+package runner
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+
+	"github.com/deltacat/dbstress/client"
+	"github.com/deltacat/dbstress/config"
+	"github.com/deltacat/dbstress/data/postgres"
+	"github.com/deltacat/dbstress/data/query"
+	"github.com/deltacat/dbstress/stress"
+)
+
+// PostgresRunner postgres runner
+type PostgresRunner struct {
+	caseRunner
+	layout   postgres.Layout
+	copyFrom bool
+}
+
+// NewPostgresRunner create a new postgres runner instance
+func NewPostgresRunner(cli client.Client, cs CaseConfig, layout postgres.Layout, copyFrom bool) PostgresRunner {
+	return PostgresRunner{
+		caseRunner: caseRunner{
+			cli:         cli,
+			cfg:         cs,
+			points:      cs.pointsConfig(),
+			concurrency: cs.Concurrent,
+		},
+		layout:   layout,
+		copyFrom: copyFrom,
+	}
+}
+
+func init() {
+	RegisterFactory(config.DriverPostgres, buildPostgresRunner)
+}
+
+// buildPostgresRunner build runner of a case on postgres connection
+func buildPostgresRunner(cfg config.Config, cf CaseConfig) (Runner, error) {
+	pc := cf.pointsConfig()
+	cof, err := cfg.FindPostgresConnection(cf.Connection)
+	if err != nil {
+		return nil, err
+	}
+	cli, err := client.NewPostgresClient(cof)
+	if err != nil {
+		return nil, err
+	}
+	layout, err := postgres.GenerateLayout(pc.Measurement, pc.SeriesKey, pc.FieldsStr, cof.Hypertable)
+	if err != nil {
+		cli.Close()
+		return nil, err
+	}
+	r := NewPostgresRunner(cli, cf, layout, cof.CopyFrom)
+	if cf.HasQueries() {
+		if r.queries, err = newSQLQueryGenerator(pc, query.Postgres, postgres.TagValueCardinality); err != nil {
+			cli.Close()
+			return nil, err
+		}
+	}
+	return &r, nil
+}
+
+// Run run the case
+func (r *PostgresRunner) Run(ctx context.Context) error {
+	if err := r.cli.Create(r.layout.GetCreateStmt()); err != nil {
+		return err
+	}
+
+	action := ActionInsert
+	if r.copyFrom {
+		action = ActionCopy
+	}
+	return r.run(ctx, action, r.doWritePostgres)
+}
+
+func (r *PostgresRunner) doWritePostgres(ctx context.Context, resultChan chan stress.WriteResult, workers int) (uint64, uint64, error) {
+	var wg sync.WaitGroup
+	wg.Add(workers)
+
+	var totalWritten uint64
+	var totalFailed uint64
+
+	for i := 0; i < workers; i++ {
+		go func() {
+			tbl := postgres.NewTableChunk(r.layout, uint64(r.cfg.BatchSize))
+
+			cfg := r.newWriteConfig(resultChan, workers, r.limiter)
+
+			// Ignore duration from a single call to Write.
+			pointsWritten, pointsFailed, _ := stress.WritePostgres(ctx, tbl, r.cli, cfg, r.copyFrom)
+			atomic.AddUint64(&totalWritten, pointsWritten)
+			atomic.AddUint64(&totalFailed, pointsFailed)
+
+			wg.Done()
+		}()
+	}
+
+	wg.Wait()
+
+	return totalWritten, totalFailed, nil
+}
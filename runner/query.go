@@ -0,0 +1,53 @@
+package runner
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+
+	"github.com/deltacat/dbstress/config"
+	"github.com/deltacat/dbstress/data/influx/point"
+	"github.com/deltacat/dbstress/data/query"
+	"github.com/deltacat/dbstress/stress"
+)
+
+// numbers of series sampled for sql query generation
+const sqlQuerySeriesN = 1000
+
+func newInfluxQueryGenerator(pc config.PointsConfig, apiVersion int, bucket string) (*query.Generator, error) {
+	dialect := query.InfluxQL
+	if apiVersion == 2 {
+		dialect = query.Flux
+	}
+	series := query.SeriesFromKeys(point.NewSeriesKeys(pc.Measurement, pc.SeriesKey, pc.SeriesN))
+	return query.NewGenerator(dialect, bucket, pc.Measurement, pc.FieldsStr, series, queryCfg.Templates, queryCfg.Window, queryCfg.Interval)
+}
+
+func newSQLQueryGenerator(pc config.PointsConfig, dialect query.Dialect, tagValueCard int) (*query.Generator, error) {
+	series := query.SampleSeries(pc.SeriesKey, tagValueCard, sqlQuerySeriesN)
+	return query.NewGenerator(dialect, "", pc.Measurement, pc.FieldsStr, series, queryCfg.Templates, queryCfg.Window, queryCfg.Interval)
+}
+
+func (r *caseRunner) doQuery(ctx context.Context, resultChan chan stress.WriteResult, workers int) (uint64, uint64, error) {
+	var wg sync.WaitGroup
+	wg.Add(workers)
+
+	var totalQueried uint64
+	var totalFailed uint64
+
+	for i := 0; i < workers; i++ {
+		go func() {
+			cfg := r.newWriteConfig(resultChan, workers, nil)
+
+			queried, failed, _ := stress.RunQueries(ctx, r.queries, r.cli, cfg)
+			atomic.AddUint64(&totalQueried, queried)
+			atomic.AddUint64(&totalFailed, failed)
+
+			wg.Done()
+		}()
+	}
+
+	wg.Wait()
+
+	return totalQueried, totalFailed, nil
+}
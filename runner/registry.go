@@ -0,0 +1,81 @@
+package runner
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/deltacat/dbstress/config"
+	"github.com/deltacat/dbstress/utils"
+)
+
+// Factory build runner of a case, whose connection is resolved to a configured connection of the driver
+type Factory func(cfg config.Config, cf CaseConfig) (Runner, error)
+
+var (
+	factoriesMu sync.RWMutex
+	factories   = map[string]Factory{}
+)
+
+// RegisterFactory register runner factory of a connection driver, backends call it in init
+func RegisterFactory(driver string, f Factory) {
+	factoriesMu.Lock()
+	defer factoriesMu.Unlock()
+	if _, dup := factories[driver]; dup {
+		panic("runner: RegisterFactory called twice for driver " + driver)
+	}
+	factories[driver] = f
+}
+
+// Drivers return drivers which have registered runner factory
+func Drivers() []string {
+	factoriesMu.RLock()
+	defer factoriesMu.RUnlock()
+	drivers := make([]string, 0, len(factories))
+	for d := range factories {
+		drivers = append(drivers, d)
+	}
+	sort.Strings(drivers)
+	return drivers
+}
+
+// ResolveConnection return the connection a case runs on, named by the case,
+// or the default connection of the case driver if no connection is named
+func ResolveConnection(cfg config.Config, cf CaseConfig) (config.ConnectionInfo, error) {
+	if cf.Connection == "" {
+		if cf.Driver == "" {
+			return config.ConnectionInfo{}, fmt.Errorf("case %q needs connection or driver: %w", cf.Name, utils.ErrInvalidArgs)
+		}
+		ci, err := cfg.FindDefaultConnection(cf.Driver)
+		if err != nil {
+			return ci, fmt.Errorf("no %s connection for case %q: %w", cf.Driver, cf.Name, err)
+		}
+		return ci, nil
+	}
+	ci, err := cfg.FindConnection(cf.Connection)
+	if err != nil {
+		return ci, fmt.Errorf("connection %q of case %q: %w", cf.Connection, cf.Name, err)
+	}
+	if cf.Driver != "" && !strings.EqualFold(cf.Driver, ci.Driver) {
+		return ci, fmt.Errorf("case %q is %s but connection %q is %s: %w", cf.Name, cf.Driver, ci.Name, ci.Driver, utils.ErrInvalidArgs)
+	}
+	return ci, nil
+}
+
+// BuildRunner build runner of a case by the factory of its connection driver
+func BuildRunner(cfg config.Config, cf CaseConfig) (Runner, error) {
+	ci, err := ResolveConnection(cfg, cf)
+	if err != nil {
+		return nil, err
+	}
+	factoriesMu.RLock()
+	f, ok := factories[ci.Driver]
+	factoriesMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("no runner for %s connection %q: %w", ci.Driver, ci.Name, utils.ErrNotSupport)
+	}
+	cf.Connection = ci.Name
+	cf.Driver = ci.Driver
+	return f(cfg, cf)
+}
@@ -1,15 +1,18 @@
 package runner
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"math"
-	"strings"
+	"net/http"
+	"os"
 	"sync"
 	"time"
 
 	"github.com/deltacat/dbstress/client"
 	"github.com/deltacat/dbstress/config"
-	"github.com/deltacat/dbstress/data/mysql"
+	"github.com/deltacat/dbstress/data/query"
 	"github.com/deltacat/dbstress/report"
 	"github.com/deltacat/dbstress/stress"
 	"github.com/deltacat/dbstress/utils"
@@ -19,125 +22,415 @@ import (
 var (
 	tick                       time.Duration
 	fast, quiet, kapacitorMode bool
+	strict                     bool // fail every case at the first failed request
 	pointsCfg                  config.PointsConfig
-	pointsN                    uint64
-	statsHost, statsDB         string
-	recordStats                bool
+	queryCfg                   config.QueryConfig
+	statsClient                client.Client // recording runtime stats if enabled
+	promServer                 *http.Server  // serving prometheus metrics if enabled
+	progress                   = ProgressAuto
+	intervalsCfg               intervalsConfig // per-interval results saved if interval is set
+	losslessStats              bool            // latency recorded in workers instead of by sink
 )
 
+type intervalsConfig struct {
+	interval time.Duration
+	dir      string
+	format   string
+}
+
+// progress view modes of running cases
+const (
+	ProgressAuto  = "auto"  // live view on terminal, plain lines otherwise
+	ProgressTTY   = "tty"   // always live view
+	ProgressPlain = "plain" // always plain lines
+	ProgressOff   = "off"
+)
+
+// caseGrace how long requests in flight at the end of the runtime are waited for,
+// a hung database could not hold the case open longer than that
+const caseGrace = 5 * time.Second
+
+// ErrCaseFailed case failed by exceeding its failure budget
+var ErrCaseFailed = errors.New("case failed")
+
+// ProgressModes all progress view modes
+var ProgressModes = []string{ProgressAuto, ProgressTTY, ProgressPlain, ProgressOff}
+
 // Runner runner interface
 type Runner interface {
-	Run() error
+	// Run run the case until finished or ctx is done
+	Run(ctx context.Context) error
 
 	// Info return a map to print log
 	Info() map[string]interface{}
 	// Result return a map to print log
 	Result() map[string]interface{}
+	// Records return report records of finished case, one per operation type
+	Records() []report.Record
 }
 
 type caseRunner struct {
-	cli client.Client
-	cfg CaseConfig
+	cli     client.Client
+	cfg     CaseConfig
+	queries *query.Generator    // only built for query cases
+	limiter *stress.RateLimiter // shared by write workers when pps or profile is set
+	profile stress.LoadProfile
+
+	points   config.PointsConfig   // resolved points config of the case
+	inFlight int64                 // requests in flight, counted when exporting prometheus metrics
+	retried  uint64                // points written after retries
+	retries  uint64                // retries sent
+	failure  string                // why the case failed, empty if not
+	dropped  uint64                // results dropped before reaching sinks
+	deadline time.Time             // end of the runtime of running case
+	budget   *stress.BudgetCounter // failure budget of running case, nil if not enabled
 
-	concurrency  int
-	totalTime    time.Duration
-	totalWritten uint64
-	totalFailed  uint64
-	throughput   uint64
+	mu          sync.Mutex
+	recorders   []*stress.LatencyRecorder // latency recorded by every worker in lossless mode
+	concurrency int
+	totalTime   time.Duration
+	results     []opResult      // one per operation type
+	records     []report.Record // reported results
 }
 
-type doWriteFunc func(resultChan chan stress.WriteResult) (uint64, uint64, error)
+// opResult result of an operation type in a case
+type opResult struct {
+	action     string
+	workers    int
+	total      uint64
+	failed     uint64
+	timeouts   uint64 // requests timed out
+	retried    uint64 // points written after retries
+	retries    uint64
+	throughput uint64
+	targetPPS  uint64
+	latency    stress.LatencyStats
+}
+
+// latencyStats latency statistics of a case, recorded by sink or by workers
+type latencyStats interface {
+	Stats(op string) stress.LatencyStats
+	Timeouts(op string) uint64
+}
+
+// caseOp an operation type and the workers running it
+type caseOp struct {
+	action  string
+	workers int
+	do      doWriteFunc
+}
+
+type doWriteFunc func(ctx context.Context, resultChan chan stress.WriteResult, workers int) (uint64, uint64, error)
 
 // Setup runner context
-func Setup(_tick time.Duration, _fast, _quiet, _kapacitorMode bool, ptsCfg config.PointsConfig, statsCfg config.StatsRecordConfig) {
+func Setup(_tick time.Duration, _fast, _quiet, _kapacitorMode bool, ptsCfg config.PointsConfig, qryCfg config.QueryConfig, statsConn *config.InfluxClientConfig, promCfg config.PrometheusConfig) {
 	fast = _fast
-	if fast {
-		tick = time.Nanosecond
-	} else {
-		tick = _tick
-	}
+	tick = _tick
 	quiet = _quiet
 	kapacitorMode = _kapacitorMode
-	recordStats = statsCfg.Enable
-	statsHost = statsCfg.Host
-	statsDB = statsCfg.Database
 	pointsCfg = ptsCfg
-	if pointsCfg.PointsN == 0 {
-		pointsN = math.MaxUint64
-	} else {
-		pointsN = pointsCfg.PointsN
+	queryCfg = qryCfg
+	if statsConn != nil && statsClient == nil {
+		setupStatsClient(*statsConn)
 	}
-	report.SetHeader([]string{"case", "connection", "action", "concur", "batch", "gzip", "start", "run", "throughput", "points", "failed"})
+	if promCfg.Enable && promServer == nil {
+		srv, err := stress.ServePrometheus(promCfg.Listen)
+		if err != nil {
+			logrus.WithError(err).WithField("listen", promCfg.Listen).Error("serve prometheus metrics failed")
+		} else {
+			promServer = srv
+			logrus.WithField("listen", promCfg.Listen).Info("serving prometheus metrics at /metrics")
+		}
+	}
+}
+
+// setupStatsClient create client recording runtime stats, stats are not recorded if failed
+func setupStatsClient(cc config.InfluxClientConfig) {
+	cc.Precision = "n" // timestamps of results are in nanoseconds
+	cli, err := client.NewInfluxClient(cc, "")
+	if err != nil {
+		logrus.WithError(err).WithField("connection", cc.Name).Error("create stats-record client failed, stats not recorded")
+		return
+	}
+	if err := cli.Create(""); err != nil {
+		logrus.WithError(err).WithField("connection", cc.Name).Warn("create stats-record database failed")
+	}
+	statsClient = cli
+}
+
+// SetProgress set progress view mode of running cases, it is always off in quiet mode
+func SetProgress(mode string) {
+	progress = mode
+}
+
+// SetStrict fail every case at the first failed request
+func SetStrict(s bool) {
+	strict = s
+}
+
+// SetLosslessStats record latency in workers instead of sending every result to latency sink,
+// so statistics are not biased when results are dropped by slow sinks
+func SetLosslessStats(lossless bool) {
+	losslessStats = lossless
+}
+
+// SetIntervals save per-interval results of every case into dir as csv or json, 0 interval to disable
+func SetIntervals(interval time.Duration, dir, format string) {
+	intervalsCfg = intervalsConfig{interval: interval, dir: dir, format: format}
+}
+
+// newProgressSink return progress sink of case as progress mode, nil if off.
+// Progress goes to stderr, so it never mixes with report printed to stdout.
+func (r *caseRunner) newProgressSink() stress.Sink {
+	if quiet || progress == ProgressOff {
+		return nil
+	}
+	tty := progress == ProgressTTY || (progress == ProgressAuto && stress.IsTerminal(os.Stderr))
+	return stress.NewProgressSink(r.concurrency, r.cfg.Name, r.cfg.Runtime.Duration, os.Stderr, tty)
 }
 
 // Close finish all runners
 func Close() {
+	if promServer != nil {
+		if err := stress.ShutdownPrometheus(promServer); err != nil {
+			logrus.WithError(err).Warn("shutdown prometheus server failed")
+		}
+		promServer = nil
+	}
+	if statsClient != nil {
+		if err := statsClient.Close(); err != nil {
+			logrus.WithError(err).Warn("close stats-record client failed")
+		}
+		statsClient = nil
+	}
 }
 
-// Report print report
-func Report() {
+// Report print report in given format, also save it to file if given
+func Report(format, file string) {
 	if !quiet {
-		fmt.Printf("\nReport: =======>\n")
-		fmt.Printf("Use point template: %s %s <timestamp>\n", pointsCfg.SeriesKey, pointsCfg.FieldsStr)
-		fmt.Printf("Use runner config: fast(%v) tick(%v)\n\n", fast, tick)
-		report.Render()
+		if format == report.FormatTable || format == "" {
+			fmt.Printf("\nReport: =======>\n")
+			fmt.Printf("Use point template: %s %s <timestamp>\n", pointsCfg.SeriesKey, pointsCfg.FieldsStr)
+			fmt.Printf("Use runner config: fast(%v) tick(%v)\n\n", fast, tick)
+		}
+		if err := report.Render(os.Stdout, format); err != nil {
+			logrus.WithError(err).Error("render report failed")
+		}
 		fmt.Println()
 	}
+	if file != "" {
+		if err := report.RenderFile(file, format); err != nil {
+			logrus.WithError(err).WithField("file", file).Error("save report failed")
+		} else {
+			logrus.WithField("file", file).Info("report saved")
+		}
+	}
+}
+
+// BuildResult build structured result of all finished cases
+func BuildResult(version string, start time.Time) report.Result {
+	return report.Result{
+		Version:  version,
+		Start:    start,
+		Finish:   time.Now(),
+		Template: pointsCfg.Measurement + "," + pointsCfg.SeriesKey + " " + pointsCfg.FieldsStr,
+		Fast:     fast,
+		Tick:     tick.String(),
+		Records:  report.Records(),
+	}
+}
+
+// FilterCases return cases whose name is in filters, all cases if no filter given
+func FilterCases(cfs []CaseConfig, filters []string) []CaseConfig {
+	if len(filters) == 0 {
+		return cfs
+	}
+	filtered := []CaseConfig{}
+	for _, cf := range cfs {
+		if utils.ArrayContainsStringIgnoreCase(filters, cf.Name) {
+			filtered = append(filtered, cf)
+		}
+	}
+	return filtered
 }
 
 // BuildAllRunners build runner from cases config
 func BuildAllRunners(cfg config.Config, cfs []CaseConfig, filters []string) []Runner {
 	runners := []Runner{}
-	for _, cf := range cfs {
-		if len(filters) > 0 {
-			if !utils.ArrayContainsStringIgnoreCase(filters, cf.Name) {
-				continue
-			}
-		}
-		if strings.Contains(strings.ToLower(cf.Name), "influx") {
-			if cof, err := cfg.FindInfluxDBConnection(cf.Connection); err == nil {
-				if cli, err := client.NewInfluxClient(cof, ""); err == nil {
-					r := NewInfluxRunner(cli, cf)
-					runners = append(runners, &r)
-				} else {
-					logrus.WithError(err).Error("create runner failed")
-				}
-			}
-		} else if strings.Contains(strings.ToLower(cf.Name), "mysql") {
-			if cof, err := cfg.FindMySQLConnection(cf.Connection); err == nil {
-				if cli, err := client.NewMySQLClient(cof); err == nil {
-					if layout, err := mysql.GenerateLayout(pointsCfg.Measurement, pointsCfg.SeriesKey, pointsCfg.FieldsStr); err == nil {
-						r := NewMySQLRunner(cli, cf, layout)
-						runners = append(runners, &r)
-					}
-				} else {
-					logrus.WithError(err).Error("create runner failed")
-				}
-			}
+	for _, cf := range FilterCases(cfs, filters) {
+		r, err := BuildRunner(cfg, cf)
+		if err != nil {
+			logrus.WithError(err).WithField("case", cf.Name).Error("create runner failed")
+			continue
 		}
+		runners = append(runners, r)
 	}
 	return runners
 }
 
-func (r *caseRunner) doInsert(doWrite doWriteFunc) error {
+// run dispatch the case by its action, doWrite is the write workload of the backend
+func (r *caseRunner) run(ctx context.Context, writeAction string, doWrite doWriteFunc) error {
+	if r.cfg.Profile != "" {
+		profile, err := stress.ParseLoadProfile(r.cfg.Profile, r.cfg.Runtime.Duration)
+		if err != nil {
+			return err
+		}
+		r.profile = profile
+		r.limiter = stress.NewRateLimiter(profile.Rate(0), uint64(r.cfg.BatchSize))
+		stop := make(chan struct{})
+		defer close(stop)
+		go r.limiter.Follow(profile, stop)
+	} else if r.cfg.PPS > 0 {
+		r.limiter = stress.NewRateLimiter(float64(r.cfg.PPS), uint64(r.cfg.BatchSize))
+	}
+	if r.cfg.OpenLoop && r.limiter == nil {
+		return fmt.Errorf("open loop case needs pps or profile: %w", utils.ErrInvalidArgs)
+	}
+	switch r.cfg.Action {
+	case ActionQuery:
+		if kapacitorMode || r.queries == nil {
+			return utils.ErrNotSupport
+		}
+		return r.doCase(ctx, caseOp{ActionQuery, r.concurrency, r.doQuery})
+	case ActionMixed:
+		if kapacitorMode || r.queries == nil {
+			return utils.ErrNotSupport
+		}
+		readers, err := splitWorkers(r.concurrency, r.cfg.ReadPercent)
+		if err != nil {
+			return err
+		}
+		return r.doCase(ctx,
+			caseOp{writeAction, r.concurrency - readers, doWrite},
+			caseOp{ActionQuery, readers, r.doQuery})
+	}
+	return r.doCase(ctx, caseOp{writeAction, r.concurrency, doWrite})
+}
+
+// splitWorkers return how many of the workers should run queries,
+// both reads and writes get at least one worker
+func splitWorkers(concurrency, readPercent int) (int, error) {
+	if concurrency < 2 || readPercent <= 0 || readPercent >= 100 {
+		return 0, fmt.Errorf("mixed case needs concurrent >= 2 and 0 < read-percent < 100, got %d and %d: %w",
+			concurrency, readPercent, utils.ErrInvalidArgs)
+	}
+	readers := int(math.Round(float64(concurrency) * float64(readPercent) / 100))
+	if readers < 1 {
+		readers = 1
+	}
+	if readers > concurrency-1 {
+		readers = concurrency - 1
+	}
+	return readers, nil
+}
+
+// targetPPS return target rate of write workers, average rate over the runtime if following a profile
+func (r *caseRunner) targetPPS() uint64 {
+	if r.profile != nil {
+		return uint64(stress.AverageRate(r.profile, r.totalTime))
+	}
+	return r.cfg.PPS
+}
+
+// newWriteConfig build write config of a worker, throttled by limiter if given, otherwise by tick
+func (r *caseRunner) newWriteConfig(resultChan chan stress.WriteResult, workers int, limiter *stress.RateLimiter) stress.WriteConfig {
+	maxPoints := r.points.PointsN
+	if maxPoints == 0 {
+		maxPoints = math.MaxUint64
+	}
+	cfg := stress.WriteConfig{
+		BatchSize: uint64(r.cfg.BatchSize),
+		MaxPoints: maxPoints / uint64(workers), // divide by concurreny
+		GzipLevel: r.cfg.Gzip,
+		Deadline:  r.deadline,
+		Limiter:   limiter,
+		OpenLoop:  r.cfg.OpenLoop && limiter != nil,
+		InFlight:  &r.inFlight,
+		Retry:     r.cfg.retryPolicy(),
+		Retried:   &r.retried,
+		Retries:   &r.retries,
+		Dropped:   &r.dropped,
+		Budget:    r.budget,
+		Results:   resultChan,
+	}
+	if limiter == nil {
+		cfg.Tick = time.Tick(r.cfg.batchTick())
+	}
+	if losslessStats {
+		cfg.Latency = stress.NewLatencyRecorder()
+		r.mu.Lock()
+		r.recorders = append(r.recorders, cfg.Latency)
+		r.mu.Unlock()
+	}
+	return cfg
+}
+
+func (r *caseRunner) doCase(ctx context.Context, ops ...caseOp) error {
+	// canceled when failure budget is exceeded, or when requests outlive the runtime by caseGrace,
+	// ctx is still checked for interruption
+	r.deadline = time.Now().Add(r.cfg.Runtime.Duration)
+	caseCtx, cancel := context.WithDeadline(ctx, r.deadline.Add(caseGrace))
+	defer cancel()
+
+	if budget := r.cfg.failureBudget(); budget.Enabled() {
+		r.budget = stress.NewBudgetCounter(budget, func(reason string) {
+			r.failure = reason
+			logrus.WithField("case", r.cfg.Name).WithField("reason", reason).Error("failure budget exceeded, stop case")
+			cancel()
+		})
+	}
 
 	sink := stress.NewMultiSink(r.concurrency)
-	sink.AddSink(stress.NewErrorSink(r.concurrency))
+	errSink := stress.NewErrorSink(r.concurrency, os.Stderr)
+	sink.AddSink("errors", errSink)
+	var latency latencyStats
+	if !losslessStats {
+		latencySink := stress.NewLatencySink(r.concurrency)
+		sink.AddSink("latency", latencySink)
+		latency = latencySink
+	}
 
-	if recordStats {
-		sink.AddSink(stress.NewInfluxDBSink(int(r.concurrency), statsHost, statsDB))
+	var statsSink *stress.InfluxDBSink
+	if statsClient != nil {
+		statsSink = stress.NewInfluxDBSink(r.concurrency, statsClient, stress.StatsTags{
+			Case:        r.cfg.Name,
+			Connection:  r.cfg.Connection,
+			BatchSize:   r.cfg.BatchSize,
+			Concurrency: r.concurrency,
+		}, os.Stderr)
+		sink.AddSink("stats-record", statsSink)
+	}
+	if promServer != nil {
+		sink.AddSink("prometheus", stress.NewPrometheusSink(r.concurrency, r.cfg.Name, r.cfg.Connection, &r.inFlight))
+	}
+	if progress := r.newProgressSink(); progress != nil {
+		sink.AddSink("progress", progress)
+	}
+	var intervals *stress.IntervalSink
+	if intervalsCfg.interval > 0 {
+		intervals = stress.NewIntervalSink(r.concurrency, intervalsCfg.interval)
+		sink.AddSink("intervals", intervals)
 	}
 
 	sink.Open()
 
 	var wg sync.WaitGroup
-	wg.Add(r.concurrency)
+	wg.Add(len(ops))
 
 	start := time.Now()
 
-	totalWritten, totalFailed, err := doWrite(sink.Chan())
-	r.totalWritten = totalWritten
-	r.totalFailed = totalFailed
+	errs := make([]error, len(ops))
+	r.results = make([]opResult, len(ops))
+	for i, op := range ops {
+		go func(i int, op caseOp) {
+			defer wg.Done()
+			res := &r.results[i]
+			res.action = op.action
+			res.workers = op.workers
+			res.total, res.failed, errs[i] = op.do(caseCtx, sink.Chan(), op.workers)
+		}(i, op)
+	}
+	wg.Wait()
 
 	r.totalTime = time.Since(start)
 	if err := r.cli.Close(); err != nil {
@@ -145,38 +438,190 @@ func (r *caseRunner) doInsert(doWrite doWriteFunc) error {
 	}
 
 	sink.Close()
-	r.throughput = (r.totalWritten - r.totalFailed) / uint64(r.totalTime.Seconds())
-	if quiet {
-		fmt.Println(r.throughput)
-	} else {
-		report.Append([]string{
-			r.cfg.Name,
-			r.cli.Connection(),
-			"insert",
-			fmt.Sprintf("%d", r.concurrency),
-			fmt.Sprintf("%d", r.cfg.BatchSize),
-			fmt.Sprintf("%d", r.cfg.Gzip),
-			fmt.Sprintf("%s", start.Local().Format("2006-01-02 15:04:05")),
-			fmt.Sprintf("%.0fs", r.totalTime.Seconds()),
-			fmt.Sprintf("%d", r.throughput),
-			fmt.Sprintf("%d", r.totalWritten),
-			fmt.Sprintf("%d", r.totalFailed)})
+	if statsSink != nil && statsSink.Failed() > 0 {
+		logrus.WithField("case", r.cfg.Name).WithField("failed flushes", statsSink.Failed()).Warn("stats-record flushes failed, recorded stats are incomplete")
 	}
 
+	if losslessStats {
+		merged := stress.NewLatencyRecorder()
+		for _, rec := range r.recorders {
+			merged.Merge(rec)
+		}
+		latency = merged
+	}
+	dropped := sink.Dropped()
+	if r.dropped > 0 {
+		dropped["all"] = r.dropped
+	}
+	if len(dropped) > 0 {
+		fields := logrus.Fields{"case": r.cfg.Name}
+		for name, n := range dropped {
+			fields["dropped "+name] = n
+		}
+		logrus.WithFields(fields).Warn("results dropped as sinks could not keep up, their statistics are incomplete")
+	}
+	// results missing from latency statistics
+	droppedResults := uint64(0)
+	if !losslessStats {
+		droppedResults = r.dropped + dropped["latency"]
+	}
+
+	intervalsFile := ""
+	if intervals != nil {
+		intervalsFile = r.saveIntervals(intervals.Intervals(), start)
+	}
+
+	errClasses := errSink.Classes()
+	var err error
+	for i := range r.results {
+		res := &r.results[i]
+		res.throughput = uint64(float64(res.total-res.failed) / r.totalTime.Seconds())
+		if res.action != ActionQuery {
+			res.targetPPS = r.targetPPS()
+		}
+		res.latency = latency.Stats(res.action)
+		res.timeouts = latency.Timeouts(res.action)
+		if res.action != ActionQuery {
+			// only writes are retried
+			res.retried, res.retries = r.retried, r.retries
+		}
+		if errs[i] != nil && err == nil {
+			err = errs[i]
+		}
+		if quiet {
+			fmt.Println(res.throughput)
+		}
+		// drops are not attributed to operation types, the case total is on the first row only
+		caseDropped := uint64(0)
+		if i == 0 {
+			caseDropped = droppedResults
+		}
+		rec := report.Record{
+			Case:        r.cfg.Name,
+			Connection:  r.cli.Connection(),
+			Action:      res.action,
+			Concurrent:  res.workers,
+			BatchSize:   r.cfg.BatchSize,
+			Gzip:        r.cfg.Gzip,
+			Template:    r.points.SeriesKey + " " + r.points.FieldsStr,
+			Start:       start,
+			RuntimeSec:  r.totalTime.Seconds(),
+			Throughput:  res.throughput,
+			TargetPPS:   res.targetPPS,
+			OpenLoop:    r.cfg.OpenLoop && res.action != ActionQuery,
+			Interrupted: ctx.Err() != nil,
+			Failure:     r.failure,
+			AchievedPPS: float64(res.total) / r.totalTime.Seconds(),
+			Points:      res.total,
+			FirstTry:    res.total - res.failed - res.retried,
+			Retried:     res.retried,
+			Retries:     res.retries,
+			Failed:      res.failed,
+			Timeouts:    res.timeouts,
+			Dropped:     caseDropped,
+			LatMeanMs:   report.DurationMs(res.latency.Mean),
+			LatStdDevMs: report.DurationMs(res.latency.StdDev),
+			LatP50Ms:    report.DurationMs(res.latency.P50),
+			LatP90Ms:    report.DurationMs(res.latency.P90),
+			LatP99Ms:    report.DurationMs(res.latency.P99),
+			LatP999Ms:   report.DurationMs(res.latency.P999),
+			LatMaxMs:    report.DurationMs(res.latency.Max),
+
+			IntervalsFile: intervalsFile,
+			Errors:        errorsOf(errClasses, res.action),
+		}
+		r.records = append(r.records, rec)
+		report.Append(rec)
+	}
+
+	// printed to stderr along with logged failures, so report printed to stdout is kept clean
+	if !quiet && report.HasErrors(r.records) {
+		fmt.Fprintf(os.Stderr, "Errors of case %s:\n", r.cfg.Name)
+		report.RenderErrors(os.Stderr, r.records)
+	}
+	if r.failure != "" {
+		return fmt.Errorf("%w: %s", ErrCaseFailed, r.failure)
+	}
 	return err
 }
 
+// errorsOf return failed requests by class of the operation type
+func errorsOf(classes []stress.ErrorClassStats, op string) []report.ErrorClass {
+	var errs []report.ErrorClass
+	for _, c := range classes {
+		if c.Op != op {
+			continue
+		}
+		errs = append(errs, report.ErrorClass{
+			Class:   c.Class,
+			Count:   c.Count,
+			Retried: c.Retried,
+			Points:  c.Points,
+			Samples: c.Samples,
+		})
+	}
+	return errs
+}
+
+// saveIntervals save per-interval results of the case, return the file name, empty if failed
+func (r *caseRunner) saveIntervals(stats []stress.IntervalStats, start time.Time) string {
+	intervals := make([]report.Interval, 0, len(stats))
+	for _, st := range stats {
+		// the last interval ends with the case
+		width := intervalsCfg.interval
+		if rest := r.totalTime - st.Offset; rest > 0 && rest < width {
+			width = rest
+		}
+		intervals = append(intervals, report.Interval{
+			Op:         st.Op,
+			Start:      st.Start,
+			ElapsedSec: st.Offset.Seconds(),
+			Requests:   st.Requests,
+			Points:     st.Points,
+			Failed:     st.Failed,
+			PPS:        float64(st.Points) / width.Seconds(),
+			LatMeanMs:  report.DurationMs(st.Latency.Mean),
+			LatP50Ms:   report.DurationMs(st.Latency.P50),
+			LatP90Ms:   report.DurationMs(st.Latency.P90),
+			LatP99Ms:   report.DurationMs(st.Latency.P99),
+			LatMaxMs:   report.DurationMs(st.Latency.Max),
+		})
+	}
+	filename := report.IntervalFileName(intervalsCfg.dir, r.cfg.Name, start, intervalsCfg.format)
+	if err := report.SaveIntervals(filename, intervalsCfg.format, intervals); err != nil {
+		logrus.WithError(err).WithField("file", filename).Error("save interval results failed")
+		return ""
+	}
+	logrus.WithField("file", filename).Debug("interval results saved")
+	return filename
+}
+
 func (r *caseRunner) Info() map[string]interface{} {
-	return map[string]interface{}{
+	m := map[string]interface{}{
 		"name":       r.cfg.Name,
 		"connection": r.cli.Connection(),
 	}
+	if r.cfg.OpenLoop {
+		m["open loop"] = true
+	}
+	return m
+}
+
+func (r *caseRunner) Records() []report.Record {
+	return r.records
 }
 
 func (r *caseRunner) Result() map[string]interface{} {
-	return map[string]interface{}{
-		"throughput":    r.throughput,
-		"total written": r.totalWritten,
+	m := map[string]interface{}{
 		"total runtime": r.totalTime.Round(time.Second),
 	}
+	for _, res := range r.results {
+		prefix := ""
+		if len(r.results) > 1 {
+			prefix = res.action + " "
+		}
+		m[prefix+"throughput"] = res.throughput
+		m[prefix+"total"] = res.total
+	}
+	return m
 }
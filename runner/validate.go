@@ -0,0 +1,91 @@
+package runner
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/deltacat/dbstress/config"
+	"github.com/deltacat/dbstress/data/influx/lineprotocol"
+	"github.com/deltacat/dbstress/stress"
+	"github.com/deltacat/dbstress/utils"
+)
+
+// ValidateCases check cases against config, return all problems found
+func ValidateCases(cfg config.Config, cfs []CaseConfig) []error {
+	errs := []error{}
+	seen := map[string]bool{}
+	for _, cf := range cfs {
+		fail := func(format string, args ...interface{}) {
+			errs = append(errs, fmt.Errorf("case %q: %s", cf.Name, fmt.Sprintf(format, args...)))
+		}
+		if cf.Name == "" {
+			fail("no name")
+		} else if key := strings.ToLower(cf.Name); seen[key] {
+			fail("name duplicated")
+		} else {
+			seen[key] = true
+		}
+
+		if ci, err := ResolveConnection(cfg, cf); err != nil {
+			if cf.Connection != "" && errors.Is(err, utils.ErrNotFound) {
+				known := []string{}
+				for _, c := range cfg.ConnectionList() {
+					known = append(known, c.Name)
+				}
+				fail("unknown connection %q, configured connections are %v", cf.Connection, known)
+			} else {
+				fail("%v", err)
+			}
+		} else if cf.Precision != "" && ci.Driver != config.DriverInfluxDB && ci.Driver != config.DriverLineProtocol {
+			fail("precision only applies to influxdb or lineprotocol connection")
+		} else if ci.Driver == config.DriverLineProtocol && cf.HasQueries() {
+			fail("queries not supported on lineprotocol connection")
+		}
+
+		switch cf.Action {
+		case "", ActionInsert, ActionQuery:
+		case ActionMixed:
+			if _, err := splitWorkers(cf.Concurrent, cf.ReadPercent); err != nil {
+				fail("%v", err)
+			}
+		default:
+			fail("unknown action %q, expect insert, query or mixed", cf.Action)
+		}
+		if cf.Concurrent <= 0 {
+			fail("concurrent should be positive")
+		}
+		if cf.BatchSize <= 0 && cf.Action != ActionQuery {
+			fail("batch-size should be positive")
+		}
+		if cf.Runtime.Duration <= 0 {
+			fail("runtime should be positive")
+		}
+		if cf.Profile != "" {
+			if _, err := stress.ParseLoadProfile(cf.Profile, cf.Runtime.Duration); err != nil {
+				fail("profile %q: %v", cf.Profile, err)
+			}
+		}
+		if cf.OpenLoop && cf.PPS == 0 && cf.Profile == "" {
+			fail("open loop needs pps or profile")
+		}
+		if cf.RetryAttempts < 0 || cf.RetryBackoff.Duration < 0 || cf.RetryMaxBackoff.Duration < 0 {
+			fail("retry-attempts, retry-backoff and retry-max-backoff should not be negative")
+		}
+		if cf.RetryAttempts > 1 && cf.Action == ActionQuery {
+			fail("retry only applies to writes")
+		}
+		if cf.MaxErrorRatio < 0 || cf.MaxErrorRatio > 1 {
+			fail("max-error-ratio should be between 0 and 1")
+		}
+		if cf.Precision != "" {
+			if _, err := lineprotocol.ParsePrecision(cf.Precision); err != nil {
+				fail("%v", err)
+			}
+		}
+		for _, err := range cf.Points.Validate() {
+			fail("points %v", err)
+		}
+	}
+	return errs
+}
@@ -0,0 +1,75 @@
+package stress
+
+import (
+	"fmt"
+	"sync/atomic"
+)
+
+// BudgetMinRequests requests done before the error ratio of a failure budget is checked,
+// so a case is not failed by the very first requests
+const BudgetMinRequests = 100
+
+// FailureBudget failed requests tolerated before a case is given up.
+// Failed attempts which are going to be retried are not counted.
+type FailureBudget struct {
+	MaxErrors     uint64  // failed requests, 0 means no limit
+	MaxErrorRatio float64 // failed requests of all requests, 0 means no limit
+	Strict        bool    // give up at the first failed request
+}
+
+// Enabled check if any limit is set
+func (b FailureBudget) Enabled() bool {
+	return b.Strict || b.MaxErrors > 0 || b.MaxErrorRatio > 0
+}
+
+// Exceeded check failed requests against the budget, return why it is exceeded, empty if not
+func (b FailureBudget) Exceeded(requests, failed uint64) string {
+	switch {
+	case failed == 0:
+		return ""
+	case b.Strict:
+		return "strict mode, failed at first error"
+	case b.MaxErrors > 0 && failed > b.MaxErrors:
+		return fmt.Sprintf("%d failed requests exceed max-errors %d", failed, b.MaxErrors)
+	case b.MaxErrorRatio > 0 && requests >= BudgetMinRequests && float64(failed)/float64(requests) > b.MaxErrorRatio:
+		return fmt.Sprintf("%d of %d requests failed, exceed max-error-ratio %g", failed, requests, b.MaxErrorRatio)
+	}
+	return ""
+}
+
+// BudgetCounter count requests of a case against its failure budget in workers,
+// so no failure is missed when results are dropped by slow sinks
+type BudgetCounter struct {
+	budget     FailureBudget
+	onExceeded func(reason string)
+
+	requests, failed uint64
+	exceeded         uint32
+}
+
+// NewBudgetCounter create a counter of the budget, onExceeded is called once when it is exceeded
+func NewBudgetCounter(budget FailureBudget, onExceeded func(reason string)) *BudgetCounter {
+	return &BudgetCounter{budget: budget, onExceeded: onExceeded}
+}
+
+// Count count a finished request, failed attempts which are going to be retried are not counted
+func (c *BudgetCounter) Count(r WriteResult) {
+	if r.Retrying {
+		return
+	}
+	requests := atomic.AddUint64(&c.requests, 1)
+	var failed uint64
+	if r.OK() {
+		failed = atomic.LoadUint64(&c.failed)
+	} else {
+		failed = atomic.AddUint64(&c.failed, 1)
+	}
+	if failed == 0 || atomic.LoadUint32(&c.exceeded) != 0 {
+		return
+	}
+	if reason := c.budget.Exceeded(requests, failed); reason != "" && atomic.CompareAndSwapUint32(&c.exceeded, 0, 1) {
+		if c.onExceeded != nil {
+			c.onExceeded(reason)
+		}
+	}
+}
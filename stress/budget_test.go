@@ -0,0 +1,64 @@
+package stress
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/deltacat/dbstress/client"
+)
+
+func TestFailureBudget_Exceeded(t *testing.T) {
+	tests := []struct {
+		budget           FailureBudget
+		requests, failed uint64
+		exceeded         bool
+	}{
+		{FailureBudget{}, 10, 10, false},
+		{FailureBudget{Strict: true}, 10, 0, false},
+		{FailureBudget{Strict: true}, 10, 1, true},
+		{FailureBudget{MaxErrors: 5}, 100, 5, false},
+		{FailureBudget{MaxErrors: 5}, 100, 6, true},
+		{FailureBudget{MaxErrorRatio: 0.1}, 10, 5, false}, // too few requests
+		{FailureBudget{MaxErrorRatio: 0.1}, 200, 20, false},
+		{FailureBudget{MaxErrorRatio: 0.1}, 200, 21, true},
+	}
+	for _, tt := range tests {
+		if got := tt.budget.Exceeded(tt.requests, tt.failed); (got != "") != tt.exceeded {
+			t.Errorf("Wrong result of %+v with %d of %d failed. Got %q, Expected exceeded: %v\n",
+				tt.budget, tt.failed, tt.requests, got, tt.exceeded)
+		}
+	}
+}
+
+func TestBudgetCounter(t *testing.T) {
+	calls := 0
+	budget := NewBudgetCounter(FailureBudget{MaxErrors: 1}, func(string) { calls++ })
+	// results are all dropped by the full queue, the budget is still counted
+	cfg := WriteConfig{Results: make(chan WriteResult), Budget: budget}
+	cfg.report(WriteResult{StatusCode: 503, Err: errors.New("busy"), Retrying: true})
+	cfg.report(WriteResult{StatusCode: 204})
+	cfg.report(WriteResult{StatusCode: 500, Err: errors.New("internal")})
+	cfg.report(WriteResult{StatusCode: 500, Err: errors.New("internal")})
+	cfg.report(WriteResult{StatusCode: 500, Err: errors.New("internal")})
+
+	if calls != 1 {
+		t.Errorf("Exceeded budget should be reported once. Got %d calls\n", calls)
+	}
+	if got, exp := budget.failed, uint64(3); got != exp {
+		t.Errorf("Retried attempts should not be counted. Got %d failed, Expected: %d\n", got, exp)
+	}
+	if got, exp := budget.requests, uint64(4); got != exp {
+		t.Errorf("Wrong requests. Got %d, Expected: %d\n", got, exp)
+	}
+}
+
+func TestBudgetCounter_strict(t *testing.T) {
+	var reason string
+	budget := NewBudgetCounter(FailureBudget{Strict: true}, func(r string) { reason = r })
+	cfg := WriteConfig{Results: make(chan WriteResult), Budget: budget}
+	cfg.report(WriteResult{StatusCode: 204})
+	cfg.report(WriteResult{Err: client.ErrTimeout})
+	if reason == "" {
+		t.Errorf("Strict mode should fail at the first error, even if its result is dropped\n")
+	}
+}
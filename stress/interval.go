@@ -0,0 +1,157 @@
+package stress
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/HdrHistogram/hdrhistogram-go"
+)
+
+// IntervalStats results of an operation type completed within an interval
+type IntervalStats struct {
+	Op       string
+	Start    time.Time
+	Offset   time.Duration // start of interval since sink opened
+	Requests uint64
+	Points   uint64 // points written (or queries answered) successfully
+	Failed   uint64
+	Latency  LatencyStats
+}
+
+type intervalKey struct {
+	op  string
+	idx int64
+}
+
+type intervalBucket struct {
+	requests, points, failed uint64
+	hist                     *hdrhistogram.Histogram // nil once summarized
+	latency                  LatencyStats
+}
+
+// IntervalSink sink interface implementation, aggregates results into buckets of fixed interval
+// by completion time, so throughput and latency over time of a case could be plotted.
+// A bucket is summarized once results of two intervals later arrive, latency of results
+// arriving even later is dropped, they are still counted.
+type IntervalSink struct {
+	Ch chan WriteResult
+
+	interval time.Duration
+	start    time.Time
+	buckets  map[intervalKey]*intervalBucket
+	open     []intervalKey // buckets not summarized yet
+	latest   int64
+	wg       sync.WaitGroup
+}
+
+// NewIntervalSink create a new interval sink with buckets of given interval
+func NewIntervalSink(nWriters int, interval time.Duration) *IntervalSink {
+	return &IntervalSink{
+		Ch:       make(chan WriteResult, 8*nWriters),
+		interval: interval,
+		buckets:  map[intervalKey]*intervalBucket{},
+	}
+}
+
+// Chan return sink chan
+func (s *IntervalSink) Chan() chan WriteResult {
+	return s.Ch
+}
+
+// Open open sink, intervals start from now
+func (s *IntervalSink) Open() {
+	s.start = time.Now()
+	s.wg.Add(1)
+	go s.run()
+}
+
+// Close close sink
+func (s *IntervalSink) Close() {
+	close(s.Ch)
+	s.wg.Wait()
+}
+
+// Intervals return results of every interval since sink opened, ordered by time then operation type.
+// Intervals without any result are included, they are where the database stalls.
+func (s *IntervalSink) Intervals() []IntervalStats {
+	ops := map[string]bool{}
+	var last int64 = -1
+	for k := range s.buckets {
+		ops[k.op] = true
+		if k.idx > last {
+			last = k.idx
+		}
+	}
+	sorted := make([]string, 0, len(ops))
+	for op := range ops {
+		sorted = append(sorted, op)
+	}
+	sort.Strings(sorted)
+
+	stats := []IntervalStats{}
+	for idx := int64(0); idx <= last; idx++ {
+		for _, op := range sorted {
+			offset := time.Duration(idx) * s.interval
+			st := IntervalStats{Op: op, Start: s.start.Add(offset), Offset: offset}
+			if b, ok := s.buckets[intervalKey{op, idx}]; ok {
+				st.Requests, st.Points, st.Failed, st.Latency = b.requests, b.points, b.failed, b.latency
+			}
+			stats = append(stats, st)
+		}
+	}
+	return stats
+}
+
+func (s *IntervalSink) run() {
+	defer s.wg.Done()
+	for r := range s.Ch {
+		s.add(r)
+	}
+	s.summarize(math.MaxInt64)
+}
+
+func (s *IntervalSink) add(r WriteResult) {
+	idx := int64(time.Duration(r.Timestamp-s.start.UnixNano()) / s.interval)
+	if idx < 0 {
+		idx = 0
+	}
+	key := intervalKey{r.Op, idx}
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &intervalBucket{hist: NewLatencyHistogram()}
+		s.buckets[key] = b
+		s.open = append(s.open, key)
+	}
+
+	b.requests++
+	if r.OK() {
+		b.points += r.Points
+	} else {
+		b.failed += r.Points
+	}
+	if r.StatusCode != 0 && b.hist != nil {
+		RecordLatency(b.hist, r.LatNs)
+	}
+
+	if idx > s.latest {
+		s.latest = idx
+		s.summarize(idx - 1)
+	}
+}
+
+// summarize summarize open buckets before given interval and release their histograms
+func (s *IntervalSink) summarize(before int64) {
+	open := s.open[:0]
+	for _, key := range s.open {
+		if key.idx >= before {
+			open = append(open, key)
+			continue
+		}
+		b := s.buckets[key]
+		b.latency = SummarizeLatency(b.hist)
+		b.hist = nil
+	}
+	s.open = open
+}
@@ -0,0 +1,53 @@
+package stress
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestIntervalSink(t *testing.T) {
+	s := NewIntervalSink(1, time.Second)
+	s.Open()
+	at := func(d time.Duration) int64 {
+		return s.start.Add(d).UnixNano()
+	}
+	s.Chan() <- WriteResult{Op: OpInsert, Points: 1000, LatNs: int64(time.Millisecond), StatusCode: 204, Timestamp: at(100 * time.Millisecond)}
+	s.Chan() <- WriteResult{Op: OpInsert, Points: 1000, LatNs: int64(3 * time.Millisecond), StatusCode: 503, Timestamp: at(900 * time.Millisecond)}
+	s.Chan() <- WriteResult{Op: OpQuery, Points: 1, LatNs: int64(time.Millisecond), StatusCode: 200, Timestamp: at(500 * time.Millisecond)}
+	// nothing completes in 2nd second
+	s.Chan() <- WriteResult{Op: OpInsert, Points: 1000, Err: errors.New("refused"), Timestamp: at(2500 * time.Millisecond)}
+	s.Chan() <- WriteResult{Op: OpInsert, Points: 1000, LatNs: int64(2 * time.Millisecond), StatusCode: 204, Timestamp: at(4200 * time.Millisecond)}
+	// late result of 1st second after it is summarized
+	s.Chan() <- WriteResult{Op: OpInsert, Points: 1000, LatNs: int64(time.Hour), StatusCode: 204, Timestamp: at(200 * time.Millisecond)}
+	s.Close()
+
+	stats := s.Intervals()
+	if got, exp := len(stats), 10; got != exp {
+		t.Fatalf("Wrong number of intervals. Got %v, Expected: %v\n%+v", got, exp, stats)
+	}
+	tests := []struct {
+		i                        int
+		op                       string
+		offset                   time.Duration
+		requests, points, failed uint64
+		p99                      time.Duration
+	}{
+		{0, OpInsert, 0, 3, 2000, 1000, 3 * time.Millisecond},
+		{1, OpQuery, 0, 1, 1, 0, time.Millisecond},
+		{2, OpInsert, time.Second, 0, 0, 0, 0},
+		{4, OpInsert, 2 * time.Second, 1, 0, 1000, 0},
+		{8, OpInsert, 4 * time.Second, 1, 1000, 0, 2 * time.Millisecond},
+	}
+	for _, tt := range tests {
+		st := stats[tt.i]
+		if st.Op != tt.op || st.Offset != tt.offset || st.Requests != tt.requests || st.Points != tt.points || st.Failed != tt.failed {
+			t.Errorf("Wrong interval %d. Got %+v, Expected: %s at %v %d requests %d points %d failed\n",
+				tt.i, st, tt.op, tt.offset, tt.requests, tt.points, tt.failed)
+		}
+		// histogram keeps 3 significant figures
+		if diff := st.Latency.P99 - tt.p99; diff < -tt.p99/100 || diff > tt.p99/100 {
+			t.Errorf("Wrong p99 of interval %d. Got %v, Expected: ~%v\n", tt.i, st.Latency.P99, tt.p99)
+		}
+	}
+}
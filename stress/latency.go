@@ -0,0 +1,180 @@
+package stress
+
+import (
+	"sync"
+	"time"
+
+	"github.com/HdrHistogram/hdrhistogram-go"
+)
+
+// latency range tracked by histograms, values are recorded in microseconds
+const (
+	minTrackableLatencyUs = 1
+	maxTrackableLatencyUs = int64(time.Hour / time.Microsecond)
+	latencySigFigs        = 3
+)
+
+// NewLatencyHistogram create a histogram tracking latency from 1µs to 1h
+func NewLatencyHistogram() *hdrhistogram.Histogram {
+	return hdrhistogram.New(minTrackableLatencyUs, maxTrackableLatencyUs, latencySigFigs)
+}
+
+// RecordLatency record a latency in nanoseconds, out of range values are clamped
+func RecordLatency(h *hdrhistogram.Histogram, latNs int64) {
+	us := latNs / int64(time.Microsecond/time.Nanosecond)
+	if us < minTrackableLatencyUs {
+		us = minTrackableLatencyUs
+	}
+	if us > maxTrackableLatencyUs {
+		us = maxTrackableLatencyUs
+	}
+	h.RecordValue(us)
+}
+
+// LatencyStats latency statistics summarized from a histogram
+type LatencyStats struct {
+	Count  int64
+	Mean   time.Duration
+	StdDev time.Duration
+	P50    time.Duration
+	P90    time.Duration
+	P99    time.Duration
+	P999   time.Duration
+	Max    time.Duration
+}
+
+// SummarizeLatency summarize a latency histogram
+func SummarizeLatency(h *hdrhistogram.Histogram) LatencyStats {
+	if h.TotalCount() == 0 {
+		return LatencyStats{}
+	}
+	us := func(v float64) time.Duration {
+		return time.Duration(v * float64(time.Microsecond))
+	}
+	return LatencyStats{
+		Count:  h.TotalCount(),
+		Mean:   us(h.Mean()),
+		StdDev: us(h.StdDev()),
+		P50:    us(float64(h.ValueAtQuantile(50))),
+		P90:    us(float64(h.ValueAtQuantile(90))),
+		P99:    us(float64(h.ValueAtQuantile(99))),
+		P999:   us(float64(h.ValueAtQuantile(99.9))),
+		Max:    us(float64(h.Max())),
+	}
+}
+
+// LatencyRecorder records latency histogram and timed out requests per operation type, not safe for concurrent use
+type LatencyRecorder struct {
+	histograms map[string]*hdrhistogram.Histogram
+	timeouts   map[string]uint64
+}
+
+// NewLatencyRecorder create a new latency recorder
+func NewLatencyRecorder() *LatencyRecorder {
+	return &LatencyRecorder{
+		histograms: map[string]*hdrhistogram.Histogram{},
+		timeouts:   map[string]uint64{},
+	}
+}
+
+// Record record latency of the result. Requests timed out are counted, and recorded with the time
+// waited for them, so the worst stalls show in percentiles. Other requests not answered have no latency.
+func (l *LatencyRecorder) Record(r WriteResult) {
+	if r.Timeout {
+		l.timeouts[r.Op]++
+	} else if r.Err != nil && r.StatusCode == 0 {
+		// no response, no latency
+		return
+	}
+	h, ok := l.histograms[r.Op]
+	if !ok {
+		h = NewLatencyHistogram()
+		l.histograms[r.Op] = h
+	}
+	RecordLatency(h, r.LatNs)
+}
+
+// Merge add all recorded by o
+func (l *LatencyRecorder) Merge(o *LatencyRecorder) {
+	for op, h := range o.histograms {
+		mine, ok := l.histograms[op]
+		if !ok {
+			mine = NewLatencyHistogram()
+			l.histograms[op] = mine
+		}
+		mine.Merge(h)
+	}
+	for op, n := range o.timeouts {
+		l.timeouts[op] += n
+	}
+}
+
+// Stats return latency statistics of given operation type
+func (l *LatencyRecorder) Stats(op string) LatencyStats {
+	if h, ok := l.histograms[op]; ok {
+		return SummarizeLatency(h)
+	}
+	return LatencyStats{}
+}
+
+// Timeouts return number of timed out requests of given operation type
+func (l *LatencyRecorder) Timeouts(op string) uint64 {
+	return l.timeouts[op]
+}
+
+// LatencySink sink interface implementation, records latency histogram and timed out requests per operation type
+type LatencySink struct {
+	Ch chan WriteResult
+
+	mu  sync.Mutex
+	rec *LatencyRecorder
+	wg  sync.WaitGroup
+}
+
+// NewLatencySink create a new latency sink
+func NewLatencySink(nWriters int) *LatencySink {
+	return &LatencySink{
+		Ch:  make(chan WriteResult, 8*nWriters),
+		rec: NewLatencyRecorder(),
+	}
+}
+
+// Chan return sink chan
+func (s *LatencySink) Chan() chan WriteResult {
+	return s.Ch
+}
+
+// Open open sink
+func (s *LatencySink) Open() {
+	s.wg.Add(1)
+	go s.run()
+}
+
+// Close close sink
+func (s *LatencySink) Close() {
+	close(s.Ch)
+	s.wg.Wait()
+}
+
+// Stats return latency statistics of given operation type
+func (s *LatencySink) Stats(op string) LatencyStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.rec.Stats(op)
+}
+
+// Timeouts return number of timed out requests of given operation type
+func (s *LatencySink) Timeouts(op string) uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.rec.Timeouts(op)
+}
+
+func (s *LatencySink) run() {
+	defer s.wg.Done()
+	for r := range s.Ch {
+		s.mu.Lock()
+		s.rec.Record(r)
+		s.mu.Unlock()
+	}
+}
@@ -0,0 +1,114 @@
+package stress
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/deltacat/dbstress/client"
+)
+
+func TestSummarizeLatency(t *testing.T) {
+	h := NewLatencyHistogram()
+	for i := 1; i <= 1000; i++ {
+		RecordLatency(h, int64(time.Duration(i)*time.Millisecond))
+	}
+
+	st := SummarizeLatency(h)
+	if st.Count != 1000 {
+		t.Errorf("Wrong count. Got %v, Expected: %v\n", st.Count, 1000)
+	}
+	tests := []struct {
+		name string
+		got  time.Duration
+		exp  time.Duration
+	}{
+		{"p50", st.P50, 500 * time.Millisecond},
+		{"p90", st.P90, 900 * time.Millisecond},
+		{"p99", st.P99, 990 * time.Millisecond},
+		{"max", st.Max, 1000 * time.Millisecond},
+		{"mean", st.Mean, 500 * time.Millisecond},
+	}
+	for _, tt := range tests {
+		// histogram keeps 3 significant figures
+		if diff := tt.got - tt.exp; diff < -tt.exp/100 || diff > tt.exp/100 {
+			t.Errorf("Wrong %s. Got %v, Expected: ~%v\n", tt.name, tt.got, tt.exp)
+		}
+	}
+}
+
+func TestRecordLatency_clamp(t *testing.T) {
+	h := NewLatencyHistogram()
+	RecordLatency(h, 0)
+	RecordLatency(h, int64(2*time.Hour))
+
+	if got := h.TotalCount(); got != 2 {
+		t.Errorf("Out of range latency should be clamped, not dropped. Got count %v\n", got)
+	}
+}
+
+func TestLatencySink_timeouts(t *testing.T) {
+	s := NewLatencySink(1)
+	s.Open()
+	s.Chan() <- WriteResult{Op: OpInsert, LatNs: int64(time.Millisecond), StatusCode: 204}
+	s.Chan() <- WriteResult{Op: OpInsert, LatNs: int64(time.Second), Err: client.ErrTimeout, Timeout: true}
+	s.Chan() <- WriteResult{Op: OpInsert, StatusCode: 503}
+	s.Chan() <- WriteResult{Op: OpInsert, Err: errors.New("connection refused")}
+	s.Close()
+
+	if got, exp := s.Timeouts(OpInsert), uint64(1); got != exp {
+		t.Errorf("Wrong timeouts. Got %v, Expected: %v\n", got, exp)
+	}
+	if got, exp := s.Stats(OpInsert).Count, int64(3); got != exp {
+		t.Errorf("Timed out requests should have latency, unanswered ones not. Got count %v, Expected: %v\n", got, exp)
+	}
+	if got, exp := (WriteResult{Timeout: true}).Status(), StatusTimeout; got != exp {
+		t.Errorf("Wrong status. Got %v, Expected: %v\n", got, exp)
+	}
+}
+
+func TestLatencyRecorder_Merge(t *testing.T) {
+	a, b := NewLatencyRecorder(), NewLatencyRecorder()
+	a.Record(WriteResult{Op: OpInsert, LatNs: int64(time.Millisecond), StatusCode: 204})
+	b.Record(WriteResult{Op: OpInsert, LatNs: int64(3 * time.Millisecond), StatusCode: 204})
+	b.Record(WriteResult{Op: OpQuery, LatNs: int64(time.Millisecond), StatusCode: 200})
+	b.Record(WriteResult{Op: OpQuery, Err: client.ErrTimeout, Timeout: true})
+
+	total := NewLatencyRecorder()
+	total.Merge(a)
+	total.Merge(b)
+	if got, exp := total.Stats(OpInsert).Count, int64(2); got != exp {
+		t.Errorf("Wrong insert count. Got %v, Expected: %v\n", got, exp)
+	}
+	if got, exp := total.Stats(OpQuery).Count, int64(2); got != exp {
+		t.Errorf("Wrong query count. Got %v, Expected: %v\n", got, exp)
+	}
+	if got, exp := total.Timeouts(OpQuery), uint64(1); got != exp {
+		t.Errorf("Wrong timeouts. Got %v, Expected: %v\n", got, exp)
+	}
+	// merged recorders are not changed
+	if got, exp := a.Stats(OpInsert).Count, int64(1); got != exp {
+		t.Errorf("Merged recorder changed. Got count %v, Expected: %v\n", got, exp)
+	}
+}
+
+func TestLatencyRecorder_timeoutLatency(t *testing.T) {
+	l := NewLatencyRecorder()
+	for i := 0; i < 99; i++ {
+		l.Record(WriteResult{Op: OpInsert, LatNs: int64(time.Millisecond), StatusCode: 204})
+	}
+	before := l.Stats(OpInsert)
+	l.Record(WriteResult{Op: OpInsert, LatNs: int64(30 * time.Second), Err: client.ErrTimeout, Timeout: true})
+	l.Record(WriteResult{Op: OpInsert, LatNs: int64(30 * time.Second), Err: client.ErrTimeout, Timeout: true})
+
+	after := l.Stats(OpInsert)
+	if after.Max < 29*time.Second || after.Max <= before.Max {
+		t.Errorf("Timeout should raise max. Got %v, before %v\n", after.Max, before.Max)
+	}
+	if after.P99 < 29*time.Second || after.P99 <= before.P99 {
+		t.Errorf("Timeouts should raise p99. Got %v, before %v\n", after.P99, before.P99)
+	}
+	if got, exp := l.Timeouts(OpInsert), uint64(2); got != exp {
+		t.Errorf("Wrong timeouts. Got %v, Expected: %v\n", got, exp)
+	}
+}
@@ -0,0 +1,135 @@
+package stress
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// pausedPoll how often a caller paused by rate 0 checks the rate again
+const pausedPoll = 10 * time.Millisecond
+
+// RateLimiter token bucket rate limiter counted in points, shared by all workers of a case.
+// A batch larger than the bucket is allowed by borrowing tokens, the borrower then waits
+// until the debt is paid back, so the long term rate is exact whatever the batch size is.
+// Rate 0 pauses sending until the rate is raised, e.g. by a profile ramping from 0.
+type RateLimiter struct {
+	mu     sync.Mutex
+	rate   float64 // tokens per second
+	burst  float64
+	tokens float64
+	last   time.Time
+	due    time.Time // next slot of the open loop timeline
+	paused time.Time // since when the rate is 0, the timeline is held meanwhile
+
+	now func() time.Time // for testing
+}
+
+// NewRateLimiter create a rate limiter of given points per second,
+// burst is the number of points which could be sent at once when idle
+func NewRateLimiter(pps float64, burst uint64) *RateLimiter {
+	return &RateLimiter{
+		rate:   pps,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		now:    time.Now,
+	}
+}
+
+// Rate return current rate in points per second
+func (l *RateLimiter) Rate() float64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.rate
+}
+
+// SetRate change rate, tokens accumulated so far are kept
+func (l *RateLimiter) SetRate(pps float64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := l.now()
+	l.advance(now)
+	switch {
+	case pps <= 0 && l.paused.IsZero():
+		l.paused = now
+	case pps > 0 && !l.paused.IsZero():
+		if !l.due.IsZero() {
+			l.due = l.due.Add(now.Sub(l.paused))
+		}
+		l.paused = time.Time{}
+	}
+	l.rate = pps
+}
+
+// Reserve take n tokens, return how long the caller has to wait before using them,
+// false without taking any while the rate is 0
+func (l *RateLimiter) Reserve(n uint64) (time.Duration, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.advance(l.now())
+	if l.rate <= 0 {
+		return 0, false
+	}
+	l.tokens -= float64(n)
+	if l.tokens >= 0 {
+		return 0, true
+	}
+	return time.Duration(-l.tokens / l.rate * float64(time.Second)), true
+}
+
+// Schedule take n tokens on a fixed timeline, return when they are due, false while the rate is 0.
+// Unlike Reserve, the timeline never skips ahead when callers fall behind, so
+// a stalled target shows up as late sends instead of fewer sends.
+// The timeline is held while the rate is 0, so the pause is not caught up afterwards.
+func (l *RateLimiter) Schedule(n uint64) (time.Time, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.rate <= 0 {
+		return time.Time{}, false
+	}
+	if l.due.IsZero() {
+		l.due = l.now()
+	}
+	at := l.due
+	l.due = l.due.Add(time.Duration(float64(n) / l.rate * float64(time.Second)))
+	return at, true
+}
+
+// Wait block until n tokens could be used, return false if deadline is passed or ctx is done before that
+func (l *RateLimiter) Wait(ctx context.Context, n uint64, deadline time.Time) bool {
+	d, ok := l.Reserve(n)
+	for !ok {
+		if !pause(ctx, deadline) {
+			return false
+		}
+		d, ok = l.Reserve(n)
+	}
+	if d <= 0 {
+		return ctx.Err() == nil
+	}
+	if wake := time.Now().Add(d); wake.After(deadline) {
+		sleepUntil(ctx, deadline)
+		return false
+	}
+	return sleepUntil(ctx, time.Now().Add(d))
+}
+
+// pause sleep while the rate is 0, return false if deadline is passed or ctx is done before checking again
+func pause(ctx context.Context, deadline time.Time) bool {
+	next := time.Now().Add(pausedPoll)
+	if next.After(deadline) {
+		sleepUntil(ctx, deadline)
+		return false
+	}
+	return sleepUntil(ctx, next)
+}
+
+func (l *RateLimiter) advance(now time.Time) {
+	if !l.last.IsZero() {
+		l.tokens += now.Sub(l.last).Seconds() * l.rate
+		if l.tokens > l.burst {
+			l.tokens = l.burst
+		}
+	}
+	l.last = now
+}
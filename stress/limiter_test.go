@@ -0,0 +1,152 @@
+package stress
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// reserve take n tokens, return how long to wait
+func reserve(l *RateLimiter, n uint64) time.Duration {
+	d, _ := l.Reserve(n)
+	return d
+}
+
+func TestRateLimiter_Reserve(t *testing.T) {
+	now := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	l := NewRateLimiter(10000, 1000)
+	l.now = func() time.Time { return now }
+
+	// the burst is free
+	if d, _ := l.Reserve(1000); d != 0 {
+		t.Errorf("Expected no wait for burst, got %v", d)
+	}
+	// a batch larger than burst borrows tokens
+	if d, exp := reserve(l, 5000), 500*time.Millisecond; d != exp {
+		t.Errorf("Wrong wait. Got %v, Expected: %v\n", d, exp)
+	}
+	// next batch queues behind the debt
+	if d, exp := reserve(l, 1000), 600*time.Millisecond; d != exp {
+		t.Errorf("Wrong wait. Got %v, Expected: %v\n", d, exp)
+	}
+
+	// after the debt is paid back, tokens refill up to burst only
+	now = now.Add(10 * time.Second)
+	if d, _ := l.Reserve(1000); d != 0 {
+		t.Errorf("Expected no wait after refill, got %v", d)
+	}
+	if d, exp := reserve(l, 1000), 100*time.Millisecond; d != exp {
+		t.Errorf("Wrong wait. Got %v, Expected: %v\n", d, exp)
+	}
+}
+
+func TestRateLimiter_SetRate(t *testing.T) {
+	now := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	l := NewRateLimiter(1000, 0)
+	l.now = func() time.Time { return now }
+
+	l.SetRate(2000)
+	if d, exp := reserve(l, 1000), 500*time.Millisecond; d != exp {
+		t.Errorf("Wrong wait. Got %v, Expected: %v\n", d, exp)
+	}
+}
+
+func TestRateLimiter_paused(t *testing.T) {
+	now := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	l := NewRateLimiter(0, 1000)
+	l.now = func() time.Time { return now }
+
+	// nothing is taken while the rate is 0, so no debt is left when the rate is raised
+	for i := 0; i < 10; i++ {
+		if _, ok := l.Reserve(1000); ok {
+			t.Fatalf("Expected paused at rate 0")
+		}
+		now = now.Add(time.Second)
+	}
+	l.SetRate(10000)
+	if d, ok := l.Reserve(1000); !ok || d != 0 {
+		t.Errorf("Expected burst free after pause. Got %v %v", d, ok)
+	}
+	if d, exp := reserve(l, 1000), 100*time.Millisecond; d != exp {
+		t.Errorf("Wrong wait. Got %v, Expected: %v\n", d, exp)
+	}
+}
+
+func TestRateLimiter_Wait_paused(t *testing.T) {
+	l := NewRateLimiter(0, 1)
+	time.AfterFunc(30*time.Millisecond, func() { l.SetRate(1000) })
+	start := time.Now()
+	if !l.Wait(context.Background(), 1, start.Add(time.Hour)) {
+		t.Fatalf("Expected wait to pass once rate is raised")
+	}
+	if d := time.Since(start); d < 30*time.Millisecond || d > time.Second {
+		t.Errorf("Wait should block until rate is raised, took %v", d)
+	}
+
+	l.SetRate(0)
+	if l.Wait(context.Background(), 1, time.Now().Add(30*time.Millisecond)) {
+		t.Errorf("Expected wait to fail at deadline while paused")
+	}
+}
+
+// schedule take n tokens on the timeline, return when they are due
+func schedule(l *RateLimiter, n uint64) time.Time {
+	at, _ := l.Schedule(n)
+	return at
+}
+
+func TestRateLimiter_Schedule(t *testing.T) {
+	now := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	l := NewRateLimiter(10000, 1000)
+	l.now = func() time.Time { return now }
+
+	if at, _ := l.Schedule(1000); !at.Equal(now) {
+		t.Errorf("Expected first batch due now, got %v", at)
+	}
+	if at, exp := schedule(l, 1000), now.Add(100*time.Millisecond); !at.Equal(exp) {
+		t.Errorf("Wrong due time. Got %v, Expected: %v\n", at, exp)
+	}
+
+	// falling behind does not skip slots, late batches are due in the past
+	now = now.Add(10 * time.Second)
+	if at, exp := schedule(l, 1000), now.Add(-9800*time.Millisecond); !at.Equal(exp) {
+		t.Errorf("Wrong due time. Got %v, Expected: %v\n", at, exp)
+	}
+}
+
+func TestRateLimiter_Wait_cancel(t *testing.T) {
+	l := NewRateLimiter(1, 1)
+	reserve(l, 1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(10*time.Millisecond, cancel)
+	start := time.Now()
+	if l.Wait(ctx, 1, start.Add(time.Hour)) {
+		t.Errorf("Expected wait to fail once canceled")
+	}
+	if d := time.Since(start); d > time.Second {
+		t.Errorf("Wait should return at cancel, took %v", d)
+	}
+}
+
+func TestRateLimiter_Schedule_paused(t *testing.T) {
+	now := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	l := NewRateLimiter(10000, 1000)
+	l.now = func() time.Time { return now }
+
+	schedule(l, 1000)
+	if at, exp := schedule(l, 1000), now.Add(100*time.Millisecond); !at.Equal(exp) {
+		t.Errorf("Wrong due time. Got %v, Expected: %v\n", at, exp)
+	}
+
+	// nothing is due while the rate is 0, the timeline is held by the pause
+	l.SetRate(0)
+	now = now.Add(5 * time.Second)
+	if _, ok := l.Schedule(1000); ok {
+		t.Fatalf("Expected paused at rate 0")
+	}
+	l.SetRate(10000)
+	if at, exp := schedule(l, 1000), now.Add(200*time.Millisecond); !at.Equal(exp) {
+		t.Errorf("Wrong due time after pause. Got %v, Expected: %v\n", at, exp)
+	}
+}
@@ -0,0 +1,188 @@
+package stress
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LoadProfile target rate (points per second) as a function of elapsed time of a case.
+//
+// Supported profiles:
+//
+//	ramp:FROM-TO                 linear ramp from FROM to TO pps over the case runtime
+//	steps:PPS@HOLD,PPS@HOLD,...  staircase, each step holds its rate for HOLD, the last rate is kept afterwards
+//	spike:BASE-PEAK@EVERY/HOLD   BASE pps, jump to PEAK pps for HOLD every EVERY
+type LoadProfile interface {
+	Rate(elapsed time.Duration) float64
+}
+
+type rampProfile struct {
+	from, to float64
+	runtime  time.Duration
+}
+
+func (p rampProfile) Rate(elapsed time.Duration) float64 {
+	if elapsed >= p.runtime || p.runtime <= 0 {
+		return p.to
+	}
+	return p.from + (p.to-p.from)*float64(elapsed)/float64(p.runtime)
+}
+
+type step struct {
+	rate float64
+	hold time.Duration
+}
+
+type stepsProfile []step
+
+func (p stepsProfile) Rate(elapsed time.Duration) float64 {
+	for _, s := range p {
+		if elapsed < s.hold {
+			return s.rate
+		}
+		elapsed -= s.hold
+	}
+	return p[len(p)-1].rate
+}
+
+type spikeProfile struct {
+	base, peak  float64
+	every, hold time.Duration
+}
+
+func (p spikeProfile) Rate(elapsed time.Duration) float64 {
+	if elapsed >= p.every && elapsed%p.every < p.hold {
+		return p.peak
+	}
+	return p.base
+}
+
+// ParseLoadProfile parse load profile, runtime is the case runtime used by ramp
+func ParseLoadProfile(s string, runtime time.Duration) (LoadProfile, error) {
+	parts := strings.SplitN(strings.TrimSpace(s), ":", 2)
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("invalid load profile %q, expect KIND:PARAMS", s)
+	}
+	kind, params := parts[0], parts[1]
+	switch kind {
+	case "ramp":
+		from, to, err := parseRange(params)
+		if err != nil {
+			return nil, fmt.Errorf("invalid ramp profile %q: %v", s, err)
+		}
+		return rampProfile{from: from, to: to, runtime: runtime}, nil
+	case "steps":
+		p := stepsProfile{}
+		for _, seg := range strings.Split(params, ",") {
+			rate, hold, err := parseAt(seg)
+			if err != nil {
+				return nil, fmt.Errorf("invalid steps profile %q: %v", s, err)
+			}
+			p = append(p, step{rate: rate, hold: hold})
+		}
+		return p, nil
+	case "spike":
+		rangeStr := strings.SplitN(params, "@", 2)
+		if len(rangeStr) != 2 {
+			return nil, fmt.Errorf("invalid spike profile %q, expect spike:BASE-PEAK@EVERY/HOLD", s)
+		}
+		base, peak, err := parseRange(rangeStr[0])
+		if err != nil {
+			return nil, fmt.Errorf("invalid spike profile %q: %v", s, err)
+		}
+		durs := strings.SplitN(rangeStr[1], "/", 2)
+		if len(durs) != 2 {
+			return nil, fmt.Errorf("invalid spike profile %q, expect spike:BASE-PEAK@EVERY/HOLD", s)
+		}
+		every, err := time.ParseDuration(durs[0])
+		if err != nil {
+			return nil, fmt.Errorf("invalid spike profile %q: %v", s, err)
+		}
+		hold, err := time.ParseDuration(durs[1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid spike profile %q: %v", s, err)
+		}
+		if every <= 0 || hold <= 0 || hold > every {
+			return nil, fmt.Errorf("invalid spike profile %q, expect 0 < HOLD <= EVERY", s)
+		}
+		return spikeProfile{base: base, peak: peak, every: every, hold: hold}, nil
+	}
+	return nil, fmt.Errorf("unknown load profile %q, expect ramp, steps or spike", kind)
+}
+
+// parseRange parse "FROM-TO"
+func parseRange(s string) (float64, float64, error) {
+	parts := strings.SplitN(s, "-", 2)
+	if len(parts) != 2 {
+		return 0, 0, fmt.Errorf("expect FROM-TO, got %q", s)
+	}
+	from, err := strconv.ParseFloat(parts[0], 64)
+	if err != nil {
+		return 0, 0, err
+	}
+	to, err := strconv.ParseFloat(parts[1], 64)
+	if err != nil {
+		return 0, 0, err
+	}
+	if from < 0 || to < 0 {
+		return 0, 0, fmt.Errorf("rate should not be negative, got %q", s)
+	}
+	return from, to, nil
+}
+
+// parseAt parse "PPS@HOLD"
+func parseAt(s string) (float64, time.Duration, error) {
+	parts := strings.SplitN(s, "@", 2)
+	if len(parts) != 2 {
+		return 0, 0, fmt.Errorf("expect PPS@HOLD, got %q", s)
+	}
+	rate, err := strconv.ParseFloat(parts[0], 64)
+	if err != nil {
+		return 0, 0, err
+	}
+	if rate < 0 {
+		return 0, 0, fmt.Errorf("rate should not be negative, got %q", s)
+	}
+	hold, err := time.ParseDuration(parts[1])
+	if err != nil {
+		return 0, 0, err
+	}
+	if hold <= 0 {
+		return 0, 0, fmt.Errorf("expect 0 < HOLD, got %q", s)
+	}
+	return rate, hold, nil
+}
+
+// profileResolution how often the limiter rate follows the profile
+const profileResolution = 100 * time.Millisecond
+
+// AverageRate return the average target rate of the profile over d
+func AverageRate(p LoadProfile, d time.Duration) float64 {
+	if d <= 0 {
+		return p.Rate(0)
+	}
+	var sum float64
+	var n int
+	for t := time.Duration(0); t < d; t += profileResolution {
+		sum += p.Rate(t)
+		n++
+	}
+	return sum / float64(n)
+}
+
+// Follow adjust limiter rate along the profile until stop is closed
+func (l *RateLimiter) Follow(p LoadProfile, stop <-chan struct{}) {
+	start := time.Now()
+	ticker := time.NewTicker(profileResolution)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			l.SetRate(p.Rate(time.Since(start)))
+		}
+	}
+}
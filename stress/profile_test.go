@@ -0,0 +1,51 @@
+package stress
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseLoadProfile(t *testing.T) {
+	tests := []struct {
+		profile string
+		elapsed time.Duration
+		exp     float64
+	}{
+		{"ramp:1000-5000", 0, 1000},
+		{"ramp:0-5000", 0, 0},
+		{"ramp:1000-5000", 5 * time.Second, 3000},
+		{"ramp:1000-5000", time.Minute, 5000},
+		{"steps:1000@10s,2000@10s,4000@5s", 0, 1000},
+		{"steps:1000@10s,2000@10s,4000@5s", 15 * time.Second, 2000},
+		{"steps:1000@10s,2000@10s,4000@5s", time.Minute, 4000},
+		{"spike:1000-9000@30s/5s", 10 * time.Second, 1000},
+		{"spike:1000-9000@30s/5s", 32 * time.Second, 9000},
+		{"spike:1000-9000@30s/5s", 36 * time.Second, 1000},
+		{"spike:1000-9000@30s/5s", 61 * time.Second, 9000},
+	}
+	for _, tt := range tests {
+		p, err := ParseLoadProfile(tt.profile, 10*time.Second)
+		if err != nil {
+			t.Errorf("%s: %v", tt.profile, err)
+			continue
+		}
+		if got := p.Rate(tt.elapsed); got != tt.exp {
+			t.Errorf("%s at %v: Got %v, Expected: %v\n", tt.profile, tt.elapsed, got, tt.exp)
+		}
+	}
+}
+
+func TestParseLoadProfile_invalid(t *testing.T) {
+	for _, s := range []string{"", "ramp", "ramp:1000", "steps:1000", "spike:1000-2000@5s/10s", "sine:1-2", "steps:1000@10s,-1000@10s", "steps:1000@0s", "steps:1000@-5s,2000@10s", "ramp:1000--5"} {
+		if _, err := ParseLoadProfile(s, time.Second); err == nil {
+			t.Errorf("Expected error for profile %q", s)
+		}
+	}
+}
+
+func TestAverageRate(t *testing.T) {
+	p, _ := ParseLoadProfile("ramp:0-2000", 10*time.Second)
+	if got := AverageRate(p, 10*time.Second); got < 990 || got > 1010 {
+		t.Errorf("Wrong average. Got %v, Expected: ~1000\n", got)
+	}
+}
@@ -0,0 +1,226 @@
+package stress
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/HdrHistogram/hdrhistogram-go"
+)
+
+// progress view settings
+const (
+	progressSparkSeconds = 30               // seconds of throughput shown by sparkline
+	progressP99Seconds   = 10               // seconds of latency the rolling p99 is computed over
+	progressPlainEvery   = 10 * time.Second // interval of plain lines when not a terminal
+)
+
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// IsTerminal check if f is a terminal
+func IsTerminal(f *os.File) bool {
+	fi, err := f.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}
+
+// ProgressSink sink interface implementation, shows live progress of a case.
+// On a terminal a status line is redrawn every second, otherwise a plain line is printed every 10s.
+type ProgressSink struct {
+	Ch chan WriteResult
+
+	name    string
+	runtime time.Duration
+	out     io.Writer
+	tty     bool
+	done    chan struct{}
+
+	start     time.Time
+	ticks     int
+	points    uint64                    // points written in current second
+	queries   uint64                    // queries answered in current second
+	latency   *hdrhistogram.Histogram   // latency of current second
+	history   []uint64                  // points written of past seconds
+	qHistory  []uint64                  // queries answered of past seconds
+	latencies []*hdrhistogram.Histogram // latency of past seconds
+	total     uint64                    // points written since start
+	qTotal    uint64                    // queries answered since start
+	errs      map[string]uint64         // failures by status code since start
+}
+
+// NewProgressSink create a new progress sink of a case printing to out
+func NewProgressSink(nWriters int, name string, runtime time.Duration, out io.Writer, tty bool) *ProgressSink {
+	return &ProgressSink{
+		Ch:      make(chan WriteResult, 8*nWriters),
+		name:    name,
+		runtime: runtime,
+		out:     out,
+		tty:     tty,
+		done:    make(chan struct{}),
+		latency: NewLatencyHistogram(),
+		errs:    map[string]uint64{},
+	}
+}
+
+// Chan return sink chan
+func (s *ProgressSink) Chan() chan WriteResult {
+	return s.Ch
+}
+
+// Open open sink
+func (s *ProgressSink) Open() {
+	s.start = time.Now()
+	go s.run()
+}
+
+// Close close sink, the final status is printed
+func (s *ProgressSink) Close() {
+	close(s.Ch)
+	<-s.done
+}
+
+func (s *ProgressSink) run() {
+	defer close(s.done)
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for {
+		select {
+		case r, ok := <-s.Ch:
+			if !ok {
+				s.roll()
+				s.render(time.Since(s.start), true)
+				return
+			}
+			s.add(r)
+		case <-ticker.C:
+			s.roll()
+			s.render(time.Since(s.start), false)
+		}
+	}
+}
+
+func (s *ProgressSink) add(r WriteResult) {
+	if r.StatusCode != 0 {
+		RecordLatency(s.latency, r.LatNs)
+	}
+	if r.OK() {
+		if r.Op == OpQuery {
+			s.queries++
+			s.qTotal++
+		} else {
+			s.points += r.Points
+			s.total += r.Points
+		}
+		return
+	}
+	s.errs[r.Status()]++
+}
+
+// roll close current second
+func (s *ProgressSink) roll() {
+	s.ticks++
+	s.history = appendLimited(s.history, s.points, progressSparkSeconds)
+	s.qHistory = appendLimited(s.qHistory, s.queries, progressSparkSeconds)
+	s.latencies = append(s.latencies, s.latency)
+	if len(s.latencies) > progressP99Seconds {
+		s.latencies = s.latencies[1:]
+	}
+	s.points, s.queries = 0, 0
+	s.latency = NewLatencyHistogram()
+}
+
+func (s *ProgressSink) render(elapsed time.Duration, final bool) {
+	if s.tty {
+		fmt.Fprintf(s.out, "\r\033[K%s", s.status(elapsed, final))
+		if final {
+			fmt.Fprintln(s.out)
+		}
+		return
+	}
+	if final || s.ticks%int(progressPlainEvery/time.Second) == 0 {
+		fmt.Fprintln(s.out, s.status(elapsed, final))
+	}
+}
+
+// status format progress in one line, throughput of last second is shown while running,
+// average of the whole case if final. Sparkline of throughput is only drawn on terminal.
+func (s *ProgressSink) status(elapsed time.Duration, final bool) string {
+	b := &strings.Builder{}
+	fmt.Fprintf(b, "[%s] %v/%v", s.name, elapsed.Round(time.Second), s.runtime)
+	if s.tty {
+		fmt.Fprintf(b, " %s", sparkline(s.history))
+	}
+	points, queries := last(s.history), last(s.qHistory)
+	if final {
+		fmt.Fprint(b, " avg")
+		if sec := elapsed.Seconds(); sec > 0 {
+			points, queries = uint64(float64(s.total)/sec), uint64(float64(s.qTotal)/sec)
+		}
+	}
+	if points > 0 || queries == 0 {
+		fmt.Fprintf(b, " %d pts/s", points)
+	}
+	if queries > 0 {
+		fmt.Fprintf(b, " %d q/s", queries)
+	}
+
+	h := NewLatencyHistogram()
+	for _, l := range s.latencies {
+		h.Merge(l)
+	}
+	fmt.Fprintf(b, " p99(%ds) %v", progressP99Seconds, SummarizeLatency(h).P99.Round(10*time.Microsecond))
+
+	if len(s.errs) > 0 {
+		codes := make([]string, 0, len(s.errs))
+		for code := range s.errs {
+			codes = append(codes, code)
+		}
+		sort.Strings(codes)
+		errs := make([]string, 0, len(codes))
+		for _, code := range codes {
+			errs = append(errs, fmt.Sprintf("%s:%d", code, s.errs[code]))
+		}
+		fmt.Fprintf(b, " errors %s", strings.Join(errs, " "))
+	}
+
+	if remaining := s.runtime - elapsed; remaining > 0 {
+		fmt.Fprintf(b, " remaining %v", remaining.Round(time.Second))
+	}
+	return b.String()
+}
+
+// sparkline draw values scaled to the max one
+func sparkline(values []uint64) string {
+	var max uint64
+	for _, v := range values {
+		if v > max {
+			max = v
+		}
+	}
+	runes := make([]rune, len(values))
+	for i, v := range values {
+		idx := 0
+		if max > 0 {
+			idx = int(v * uint64(len(sparkBlocks)-1) / max)
+		}
+		runes[i] = sparkBlocks[idx]
+	}
+	return string(runes)
+}
+
+func appendLimited(values []uint64, v uint64, limit int) []uint64 {
+	values = append(values, v)
+	if len(values) > limit {
+		values = values[1:]
+	}
+	return values
+}
+
+func last(values []uint64) uint64 {
+	if len(values) == 0 {
+		return 0
+	}
+	return values[len(values)-1]
+}
@@ -0,0 +1,54 @@
+package stress
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSparkline(t *testing.T) {
+	tests := []struct {
+		values []uint64
+		exp    string
+	}{
+		{nil, ""},
+		{[]uint64{0, 0}, "▁▁"},
+		{[]uint64{0, 7, 14}, "▁▄█"},
+		{[]uint64{5, 5}, "██"},
+	}
+	for _, tt := range tests {
+		if got := sparkline(tt.values); got != tt.exp {
+			t.Errorf("Wrong sparkline of %v. Got %q, Expected: %q\n", tt.values, got, tt.exp)
+		}
+	}
+}
+
+func TestProgressSink_status(t *testing.T) {
+	out := &bytes.Buffer{}
+	s := NewProgressSink(1, "case1", 30*time.Second, out, false)
+	s.add(WriteResult{Op: OpInsert, Points: 5000, StatusCode: 204, LatNs: int64(time.Millisecond)})
+	s.add(WriteResult{Op: OpInsert, Points: 5000, StatusCode: 204, LatNs: int64(2 * time.Millisecond)})
+	s.add(WriteResult{Op: OpInsert, Points: 5000, StatusCode: 503, LatNs: int64(time.Millisecond)})
+	s.add(WriteResult{Op: OpQuery, Points: 1, StatusCode: 200, LatNs: int64(time.Millisecond)})
+	s.add(WriteResult{Op: OpInsert, Points: 5000, Err: errors.New("refused")})
+	s.roll()
+
+	got := s.status(10*time.Second, false)
+	for _, exp := range []string{"[case1] 10s/30s", "10000 pts/s", "1 q/s", "p99(10s) 2ms", "errors 503:1 error:1", "remaining 20s"} {
+		if !strings.Contains(got, exp) {
+			t.Errorf("Status %q should contain %q\n", got, exp)
+		}
+	}
+
+	// plain lines are only printed every 10s, and at last
+	s.render(time.Second, false)
+	if out.Len() != 0 {
+		t.Errorf("Unexpected plain line %q\n", out.String())
+	}
+	s.render(time.Second, true)
+	if !strings.HasSuffix(out.String(), "\n") || strings.Contains(out.String(), "\r") {
+		t.Errorf("Wrong plain line %q\n", out.String())
+	}
+}
@@ -0,0 +1,138 @@
+package stress
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// labels of prometheus metrics
+var (
+	promOpLabels     = []string{"case", "connection", "op"}
+	promStatusLabels = []string{"case", "connection", "op", "status"}
+)
+
+var (
+	promRegistry = prometheus.NewRegistry()
+
+	promPointsWritten = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "dbstress",
+		Name:      "points_written_total",
+		Help:      "Points written (or queries answered) successfully.",
+	}, promOpLabels)
+	promPointsFailed = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "dbstress",
+		Name:      "points_failed_total",
+		Help:      "Points (or queries) failed, by status code, error if no response.",
+	}, promStatusLabels)
+	promRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "dbstress",
+		Name:      "requests_total",
+		Help:      "Batches (or queries) sent, by status code, error if no response.",
+	}, promStatusLabels)
+	promLatency = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "dbstress",
+		Name:      "request_duration_seconds",
+		Help:      "Latency of batches (or queries), by status code.",
+		Buckets:   prometheus.ExponentialBuckets(0.001, 2, 16), // 1ms to ~33s
+	}, promStatusLabels)
+)
+
+func init() {
+	promRegistry.MustRegister(
+		prometheus.NewGoCollector(),
+		prometheus.NewProcessCollector(prometheus.ProcessCollectorOpts{}),
+		promPointsWritten, promPointsFailed, promRequests, promLatency)
+}
+
+// ServePrometheus serve prometheus metrics at listen address under /metrics,
+// shutdown the returned server when done
+func ServePrometheus(listen string) (*http.Server, error) {
+	ln, err := net.Listen("tcp", listen)
+	if err != nil {
+		return nil, err
+	}
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(promRegistry, promhttp.HandlerOpts{}))
+	srv := &http.Server{Handler: mux}
+	go srv.Serve(ln)
+	return srv, nil
+}
+
+// ShutdownPrometheus shutdown prometheus server, waiting a while for in-progress scrapes
+func ShutdownPrometheus(srv *http.Server) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return srv.Shutdown(ctx)
+}
+
+// PrometheusSink sink interface implementation, exports results of a case as prometheus metrics
+type PrometheusSink struct {
+	Ch chan WriteResult
+
+	labels   prometheus.Labels
+	inFlight prometheus.Collector
+	wg       sync.WaitGroup
+}
+
+// NewPrometheusSink create a new prometheus sink of a case,
+// inFlight is the counter of requests in flight shared with workers
+func NewPrometheusSink(nWriters int, caseName, connection string, inFlight *int64) *PrometheusSink {
+	labels := prometheus.Labels{"case": caseName, "connection": connection}
+	return &PrometheusSink{
+		Ch:     make(chan WriteResult, 8*nWriters),
+		labels: labels,
+		inFlight: prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace:   "dbstress",
+			Name:        "requests_in_flight",
+			Help:        "Batches (or queries) waiting for response.",
+			ConstLabels: labels,
+		}, func() float64 {
+			return float64(atomic.LoadInt64(inFlight))
+		}),
+	}
+}
+
+// Chan return sink chan
+func (s *PrometheusSink) Chan() chan WriteResult {
+	return s.Ch
+}
+
+// Open open sink
+func (s *PrometheusSink) Open() {
+	promRegistry.Register(s.inFlight)
+	s.wg.Add(1)
+	go s.run()
+}
+
+// Close close sink
+func (s *PrometheusSink) Close() {
+	close(s.Ch)
+	s.wg.Wait()
+	promRegistry.Unregister(s.inFlight)
+}
+
+func (s *PrometheusSink) run() {
+	defer s.wg.Done()
+	for r := range s.Ch {
+		status := r.Status()
+		op := prometheus.Labels{"case": s.labels["case"], "connection": s.labels["connection"], "op": r.Op}
+		withStatus := prometheus.Labels{"case": s.labels["case"], "connection": s.labels["connection"], "op": r.Op, "status": status}
+
+		promRequests.With(withStatus).Inc()
+		if r.OK() {
+			promPointsWritten.With(op).Add(float64(r.Points))
+		} else {
+			promPointsFailed.With(withStatus).Add(float64(r.Points))
+		}
+		if r.StatusCode != 0 {
+			promLatency.With(withStatus).Observe(time.Duration(r.LatNs).Seconds())
+		}
+	}
+}
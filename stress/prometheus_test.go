@@ -0,0 +1,32 @@
+package stress
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestPrometheusSink(t *testing.T) {
+	var inFlight int64
+	s := NewPrometheusSink(1, "prom-test", "conn", &inFlight)
+	s.Open()
+	s.Chan() <- WriteResult{Op: OpInsert, Points: 1000, LatNs: 1e6, StatusCode: 204}
+	s.Chan() <- WriteResult{Op: OpInsert, Points: 1000, LatNs: 1e6, StatusCode: 204}
+	s.Chan() <- WriteResult{Op: OpInsert, Points: 1000, LatNs: 1e6, StatusCode: 503}
+	s.Chan() <- WriteResult{Op: OpInsert, Points: 1000, Err: errors.New("refused")}
+	s.Close()
+
+	if got, exp := testutil.ToFloat64(promPointsWritten.WithLabelValues("prom-test", "conn", OpInsert)), 2000.0; got != exp {
+		t.Errorf("Wrong points written. Got %v, Expected: %v\n", got, exp)
+	}
+	if got, exp := testutil.ToFloat64(promPointsFailed.WithLabelValues("prom-test", "conn", OpInsert, "503")), 1000.0; got != exp {
+		t.Errorf("Wrong points failed of 503. Got %v, Expected: %v\n", got, exp)
+	}
+	if got, exp := testutil.ToFloat64(promPointsFailed.WithLabelValues("prom-test", "conn", OpInsert, "error")), 1000.0; got != exp {
+		t.Errorf("Wrong points failed of error. Got %v, Expected: %v\n", got, exp)
+	}
+	if got, exp := testutil.ToFloat64(promRequests.WithLabelValues("prom-test", "conn", OpInsert, "204")), 2.0; got != exp {
+		t.Errorf("Wrong requests of 204. Got %v, Expected: %v\n", got, exp)
+	}
+}
@@ -0,0 +1,51 @@
+package stress
+
+import (
+	"context"
+	"time"
+
+	"github.com/deltacat/dbstress/client"
+	"github.com/deltacat/dbstress/data/query"
+)
+
+// RunQueries takes in a query generator, a client and a WriteConfig (BatchSize is ignored).
+// It keeps sending generated queries to the target until one of the following conditions is met.
+// 1. We've sent MaxPoints queries specified in the WriteConfig.
+// 2. We've passed the Deadline specified in the WriteConfig.
+// 3. ctx is done.
+func RunQueries(ctx context.Context, gen *query.Generator, c client.Client, cfg WriteConfig) (uint64, uint64, time.Duration) {
+	if cfg.Results == nil {
+		panic("Results Channel on WriteConfig cannot be nil")
+	}
+	var queryCount uint64
+	var failedCount uint64
+
+	start := time.Now()
+	t := time.Now()
+
+	for {
+		if t.After(cfg.Deadline) || queryCount >= cfg.MaxPoints || ctx.Err() != nil {
+			break
+		}
+		queryCount++
+
+		_, q := gen.Next()
+		if err := sendQuery(ctx, c, q, &cfg); err != nil {
+			failedCount++
+		}
+		var ok bool
+		if t, ok = cfg.next(ctx); !ok {
+			break
+		}
+	}
+
+	return queryCount, failedCount, time.Since(start)
+}
+
+func sendQuery(ctx context.Context, c client.Client, q string, cfg *WriteConfig) error {
+	done := cfg.begin()
+	lat, status, body, err := c.Query(ctx, q)
+	done()
+	cfg.report(WriteResult{Op: OpQuery, Points: 1, LatNs: lat, StatusCode: status, Body: body, Err: err, Attempt: 1})
+	return err
+}
@@ -0,0 +1,85 @@
+package stress
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/deltacat/dbstress/client"
+)
+
+// RetryPolicy retry of failed batches the way ingestion agents do under backpressure,
+// only rate limited (429), unavailable (503) and timed out requests are retried
+type RetryPolicy struct {
+	MaxAttempts int           // attempts of a batch including the first one, no retry if less than 2
+	Backoff     time.Duration // delay before the first retry, doubled on every retry
+	MaxBackoff  time.Duration // cap of the delay, also of the delay asked by Retry-After
+}
+
+// Retryable check if a failed request is worth retrying
+func Retryable(statusCode int, err error) bool {
+	return statusCode == http.StatusTooManyRequests || statusCode == http.StatusServiceUnavailable || client.IsTimeout(err)
+}
+
+// Delay return delay before retrying after given attempt (1 for the first try),
+// Retry-After of the response wins over the backoff
+func (p RetryPolicy) Delay(attempt int, err error) time.Duration {
+	d := client.RetryAfter(err)
+	if d <= 0 {
+		d = p.Backoff
+		for i := 1; i < attempt && (p.MaxBackoff <= 0 || d < p.MaxBackoff); i++ {
+			d *= 2
+		}
+	}
+	if p.MaxBackoff > 0 && d > p.MaxBackoff {
+		d = p.MaxBackoff
+	}
+	return d
+}
+
+// send run attempts of a request until it succeeds, every attempt is reported.
+// A failed attempt is retried following cfg.Retry, unless the retry would start after the deadline or ctx is done.
+// Latency of an attempt is measured from the intended send time of the request in open loop,
+// otherwise from the start of its first attempt, so time queued and backing off before retries is included.
+// Return error of the last attempt.
+func (cfg *WriteConfig) send(ctx context.Context, intended time.Time, attempt func() WriteResult) error {
+	origin := intended
+	for n := 1; ; n++ {
+		start := time.Now()
+		if origin.IsZero() {
+			origin = start
+		}
+		r := attempt()
+		r.LatNs += queuedNs(origin, start)
+		r.Attempt = n
+		at, retry := cfg.retryAt(n, r)
+		r.Retrying = retry
+		cfg.report(r)
+		if r.Err == nil {
+			if n > 1 && cfg.Retried != nil {
+				atomic.AddUint64(cfg.Retried, r.Points)
+			}
+			return nil
+		}
+		if !retry {
+			return r.Err
+		}
+		if cfg.Retries != nil {
+			atomic.AddUint64(cfg.Retries, 1)
+		}
+		if !sleepUntil(ctx, at) {
+			return r.Err
+		}
+	}
+}
+
+// retryAt return when to retry the attempt, false if it succeeded or should not be retried
+func (cfg *WriteConfig) retryAt(attempt int, r WriteResult) (time.Time, bool) {
+	p := cfg.Retry
+	if r.Err == nil || p == nil || attempt >= p.MaxAttempts || !Retryable(r.StatusCode, r.Err) {
+		return time.Time{}, false
+	}
+	at := time.Now().Add(p.Delay(attempt, r.Err))
+	return at, !at.After(cfg.Deadline)
+}
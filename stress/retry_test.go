@@ -0,0 +1,127 @@
+package stress
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/deltacat/dbstress/client"
+)
+
+func TestRetryPolicy_Delay(t *testing.T) {
+	p := RetryPolicy{MaxAttempts: 10, Backoff: 100 * time.Millisecond, MaxBackoff: time.Second}
+	tests := []struct {
+		attempt int
+		err     error
+		exp     time.Duration
+	}{
+		{1, client.ErrTimeout, 100 * time.Millisecond},
+		{2, client.ErrTimeout, 200 * time.Millisecond},
+		{4, client.ErrTimeout, 800 * time.Millisecond},
+		{5, client.ErrTimeout, time.Second},
+		{100, client.ErrTimeout, time.Second},
+		{1, &client.StatusError{StatusCode: 429, RetryAfter: 500 * time.Millisecond}, 500 * time.Millisecond},
+		{1, &client.StatusError{StatusCode: 503, RetryAfter: time.Minute}, time.Second},
+	}
+	for _, tt := range tests {
+		if got := p.Delay(tt.attempt, tt.err); got != tt.exp {
+			t.Errorf("Wrong delay after attempt %d of %v. Got %v, Expected: %v\n", tt.attempt, tt.err, got, tt.exp)
+		}
+	}
+}
+
+func TestWriteConfig_send(t *testing.T) {
+	busy := &client.StatusError{StatusCode: 503}
+	tests := []struct {
+		name     string
+		results  []WriteResult
+		attempts int
+		retried  uint64
+		failed   bool
+	}{
+		{"first try", []WriteResult{{StatusCode: 204}}, 1, 0, false},
+		{"retried", []WriteResult{{StatusCode: 503, Err: busy}, {Err: client.ErrTimeout}, {StatusCode: 204}}, 3, 100, false},
+		{"attempts used up", []WriteResult{{StatusCode: 503, Err: busy}, {StatusCode: 503, Err: busy}, {StatusCode: 503, Err: busy}}, 3, 0, true},
+		{"not retryable", []WriteResult{{StatusCode: 400, Err: errors.New("bad request")}}, 1, 0, true},
+	}
+	for _, tt := range tests {
+		results := make(chan WriteResult, 10)
+		var retried, retries uint64
+		cfg := WriteConfig{
+			Deadline: time.Now().Add(time.Minute),
+			Results:  results,
+			Retry:    &RetryPolicy{MaxAttempts: 3, Backoff: time.Millisecond},
+			Retried:  &retried,
+			Retries:  &retries,
+		}
+		n := 0
+		err := cfg.send(context.Background(), time.Time{}, func() WriteResult {
+			r := tt.results[n]
+			r.Points = 100
+			n++
+			return r
+		})
+		close(results)
+		if n != tt.attempts || uint64(len(results)) != uint64(tt.attempts) || retries != uint64(tt.attempts-1) {
+			t.Errorf("%s: wrong attempts. Got %d sent %d reported %d retries, Expected: %d\n", tt.name, n, len(results), retries, tt.attempts)
+		}
+		if retried != tt.retried || (err != nil) != tt.failed {
+			t.Errorf("%s: wrong outcome. Got retried %d err %v, Expected: retried %d failed %v\n", tt.name, retried, err, tt.retried, tt.failed)
+		}
+		last := 0
+		for r := range results {
+			last = r.Attempt
+		}
+		if last != tt.attempts {
+			t.Errorf("%s: wrong attempt of last result. Got %d, Expected: %d\n", tt.name, last, tt.attempts)
+		}
+	}
+}
+
+func TestWriteConfig_send_deadline(t *testing.T) {
+	cfg := WriteConfig{
+		Deadline: time.Now().Add(10 * time.Millisecond),
+		Results:  make(chan WriteResult, 10),
+		Retry:    &RetryPolicy{MaxAttempts: 3, Backoff: time.Second},
+	}
+	n := 0
+	err := cfg.send(context.Background(), time.Time{}, func() WriteResult {
+		n++
+		return WriteResult{Err: client.ErrTimeout}
+	})
+	if err == nil || n != 1 {
+		t.Errorf("Retry after deadline should be given up. Got %d attempts, err %v\n", n, err)
+	}
+}
+
+func TestWriteConfig_send_latency(t *testing.T) {
+	results := make(chan WriteResult, 10)
+	cfg := WriteConfig{
+		Deadline: time.Now().Add(time.Minute),
+		Results:  results,
+		Retry:    &RetryPolicy{MaxAttempts: 3, Backoff: 20 * time.Millisecond},
+	}
+	// the request was due 50ms ago in open loop
+	intended := time.Now().Add(-50 * time.Millisecond)
+	n := 0
+	cfg.send(context.Background(), intended, func() WriteResult {
+		n++
+		if n == 1 {
+			return WriteResult{StatusCode: 503, Err: &client.StatusError{StatusCode: 503}, LatNs: int64(time.Millisecond)}
+		}
+		return WriteResult{StatusCode: 204, LatNs: int64(time.Millisecond)}
+	})
+	close(results)
+
+	var lats []time.Duration
+	for r := range results {
+		lats = append(lats, time.Duration(r.LatNs))
+	}
+	if len(lats) != 2 || lats[0] < 50*time.Millisecond {
+		t.Fatalf("First attempt should be measured from intended time. Got %v\n", lats)
+	}
+	if lats[1] < 70*time.Millisecond {
+		t.Errorf("Retry should be measured from intended time through backoff. Got %v\n", lats[1])
+	}
+}
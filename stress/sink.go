@@ -2,13 +2,17 @@ package stress
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
-	"os"
+	"io"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/deltacat/dbstress/client"
+	"github.com/deltacat/dbstress/utils"
 )
 
 // Sink sink interface
@@ -18,24 +22,43 @@ type Sink interface {
 	Close()
 }
 
-// ErrorSink sink interface implementation for errors
-type ErrorSink struct {
-	Ch     chan WriteResult
-	strict bool
+// error aggregation of ErrorSink
+const (
+	// ErrorLogInterval a class of failures is logged at most once in the interval
+	ErrorLogInterval = 10 * time.Second
+	errorSamples     = 3   // distinct bodies or error messages kept per class
+	errorSampleLen   = 200 // samples are truncated to the length
+)
 
-	wg sync.WaitGroup
+// ErrorClassStats failed requests of a class of an operation type
+type ErrorClassStats struct {
+	Op      string
+	Class   string
+	Count   uint64   // failed requests, including attempts which were retried
+	Retried uint64   // failed attempts which were retried
+	Points  uint64   // points of failed requests
+	Samples []string // first few distinct bodies or error messages
+
+	logged     time.Time // last time logged
+	suppressed uint64    // failures not logged since then
 }
 
-// NewErrorSink create a new error sink
-func NewErrorSink(nWriters int) *ErrorSink {
-	s := &ErrorSink{
-		Ch: make(chan WriteResult, 8*nWriters),
-	}
-
-	s.wg.Add(1)
-	go s.checkErrors()
+// ErrorSink sink interface implementation for errors, aggregates failed requests by class
+// with rate limited logging
+type ErrorSink struct {
+	Ch      chan WriteResult
+	out     io.Writer
+	classes map[string]*ErrorClassStats // keyed by op and class
+	wg      sync.WaitGroup
+}
 
-	return s
+// NewErrorSink create a new error sink logging failures to out
+func NewErrorSink(nWriters int, out io.Writer) *ErrorSink {
+	return &ErrorSink{
+		Ch:      make(chan WriteResult, 8*nWriters),
+		out:     out,
+		classes: map[string]*ErrorClassStats{},
+	}
 }
 
 // Open open sink
@@ -50,25 +73,78 @@ func (s *ErrorSink) Close() {
 	s.wg.Wait()
 }
 
+// Classes return failed requests by class, ordered by operation type and count, should be called after Close
+func (s *ErrorSink) Classes() []ErrorClassStats {
+	classes := make([]ErrorClassStats, 0, len(s.classes))
+	for _, st := range s.classes {
+		classes = append(classes, *st)
+	}
+	sort.Slice(classes, func(i, j int) bool {
+		if classes[i].Op != classes[j].Op {
+			return classes[i].Op < classes[j].Op
+		}
+		if classes[i].Count != classes[j].Count {
+			return classes[i].Count > classes[j].Count
+		}
+		return classes[i].Class < classes[j].Class
+	})
+	return classes
+}
+
 func (s *ErrorSink) checkErrors() {
 	defer s.wg.Done()
-
-	const timeFormat = "[2006-01-02 15:04:05]"
 	for r := range s.Ch {
-		if r.Err != nil {
-			fmt.Fprintln(os.Stderr, time.Now().Format(timeFormat), "Error sending write:", r.Err.Error())
-			continue
+		if !r.OK() {
+			s.aggregate(r, time.Now())
 		}
+	}
+}
 
-		if r.StatusCode != 204 {
-			fmt.Fprintln(os.Stderr, time.Now().Format(timeFormat), "Unexpected write: status", r.StatusCode, ", body:", r.Body)
-		}
+// aggregate count the failed request into its class, logged unless the class is logged recently
+func (s *ErrorSink) aggregate(r WriteResult, now time.Time) {
+	class := client.Classify(r.StatusCode, r.Body, r.Err)
+	key := r.Op + " " + class
+	st, ok := s.classes[key]
+	if !ok {
+		st = &ErrorClassStats{Op: r.Op, Class: class}
+		s.classes[key] = st
+	}
+	st.Count++
+	st.Points += r.Points
+	if r.Retrying {
+		st.Retried++
+	}
+	sample := errorSample(r)
+	if len(st.Samples) < errorSamples && !utils.ArrayContainsString(st.Samples, sample) {
+		st.Samples = append(st.Samples, sample)
+	}
 
-		// If we're running in strict mode then we give up at the first error.
-		if s.strict && (r.Err != nil || r.StatusCode != 204) {
-			os.Exit(1)
-		}
+	if now.Sub(st.logged) < ErrorLogInterval {
+		st.suppressed++
+		return
+	}
+	const timeFormat = "[2006-01-02 15:04:05]"
+	more := ""
+	if st.suppressed > 0 {
+		more = fmt.Sprintf(" (%d more since last logged)", st.suppressed)
 	}
+	fmt.Fprintf(s.out, "%s %s failed, %s%s: %s\n", now.Format(timeFormat), r.Op, class, more, sample)
+	st.logged, st.suppressed = now, 0
+}
+
+// errorSample return body of the failed request, or error message if no body
+func errorSample(r WriteResult) string {
+	sample := strings.TrimSpace(r.Body)
+	if sample == "" && r.Err != nil {
+		sample = r.Err.Error()
+	}
+	if sample == "" {
+		sample = fmt.Sprintf("status %d", r.StatusCode)
+	}
+	if len(sample) > errorSampleLen {
+		sample = sample[:errorSampleLen] + "..."
+	}
+	return sample
 }
 
 // Chan return sink chan
@@ -76,12 +152,15 @@ func (s *ErrorSink) Chan() chan WriteResult {
 	return s.Ch
 }
 
-// MultiSink sink interface implementation for multi
+// MultiSink sink interface implementation for multi, fans results out to sinks without blocking.
+// Chan of every sink is its bounded queue, results are dropped and counted if the queue is full.
 type MultiSink struct {
-	sinks []Sink
-	Ch    chan WriteResult
-	wg    sync.WaitGroup
-	open  bool
+	sinks   []Sink
+	names   []string
+	dropped []uint64 // results dropped per sink
+	Ch      chan WriteResult
+	wg      sync.WaitGroup
+	open    bool
 }
 
 // NewMultiSink create a new multi sink
@@ -108,18 +187,29 @@ func (s *MultiSink) Open() {
 
 func (s *MultiSink) run() {
 	defer s.wg.Done()
-	const timeFormat = "[2006-01-02 15:04:05]"
 	for r := range s.Ch {
-		for _, sink := range s.sinks {
+		for i, sink := range s.sinks {
 			select {
 			case sink.Chan() <- r:
 			default:
-				fmt.Fprintln(os.Stderr, time.Now().Format(timeFormat), "Failed to send to sin")
+				s.dropped[i]++
 			}
 		}
 	}
 }
 
+// Dropped return number of results dropped by name of sinks, only sinks dropped any are included.
+// It should be called after Close.
+func (s *MultiSink) Dropped() map[string]uint64 {
+	dropped := map[string]uint64{}
+	for i, n := range s.dropped {
+		if n > 0 {
+			dropped[s.names[i]] += n
+		}
+	}
+	return dropped
+}
+
 // Close close sink
 func (s *MultiSink) Close() {
 	close(s.Ch)
@@ -130,43 +220,72 @@ func (s *MultiSink) Close() {
 	}
 }
 
-// AddSink add sink
-func (s *MultiSink) AddSink(sink Sink) error {
+// AddSink add sink, name is used to report results dropped by it
+func (s *MultiSink) AddSink(name string, sink Sink) error {
 	if s.open {
 		return errors.New("Cannot add sink to open multiSink")
 	}
 
 	s.sinks = append(s.sinks, sink)
+	s.names = append(s.names, name)
+	s.dropped = append(s.dropped, 0)
 
 	return nil
 }
 
-// InfluxDBSink implement sink interface
+// StatsTags tags of runtime statistics recorded by InfluxDBSink
+type StatsTags struct {
+	Case        string
+	Connection  string
+	BatchSize   int
+	Concurrency int
+}
+
+// StatsFlushTimeout a flush of stats is given up after the timeout, so a hung stats server does not block the sink
+const StatsFlushTimeout = 10 * time.Second
+
+// InfluxDBSink implement sink interface, records runtime statistics of every request into influxdb
 type InfluxDBSink struct {
 	Ch     chan WriteResult
 	client client.Client
+	tags   string    // tags of the case in line protocol, with leading comma
+	out    io.Writer // failed flushes are logged here
 	buf    *bytes.Buffer
 	ticker *time.Ticker
-}
+	wg     sync.WaitGroup
 
-// NewInfluxDBSink create a new InfluxDBSink instance
-func NewInfluxDBSink(nWriters int, url, db string) *InfluxDBSink {
-	cfg := client.InfluxConfig{
-		URL:         url,
-		APIVersion:  1,
-		Precision:   "ns",
-		Consistency: "any",
-	}
-	cfg.V1.Database = db
-	cfg.V1.RetentionPolicy = "autogen"
-
-	cli, _ := client.NewInfluxClient(cfg, "")
+	failed     uint64    // flushes failed
+	logged     time.Time // last time a failed flush is logged
+	suppressed uint64    // failed flushes not logged since then
+}
 
+// NewInfluxDBSink create a new InfluxDBSink instance writing via cli, which should be created by
+// client.NewInfluxClient of a connection with nanosecond precision, the database (or bucket) should exist.
+// Failed flushes are logged to out at most once in ErrorLogInterval.
+func NewInfluxDBSink(nWriters int, cli client.Client, tags StatsTags, out io.Writer) *InfluxDBSink {
 	return &InfluxDBSink{
 		Ch:     make(chan WriteResult, 8*nWriters),
 		client: cli,
-		buf:    bytes.NewBuffer(nil),
+		tags: fmt.Sprintf(",case=%s,connection=%s,batch_size=%d,concurrency=%d",
+			escapeTag(tags.Case), escapeTag(tags.Connection), tags.BatchSize, tags.Concurrency),
+		out: out,
+		buf: bytes.NewBuffer(nil),
+	}
+}
+
+// Failed return number of failed flushes, whose stats are lost, should be called after Close
+func (s *InfluxDBSink) Failed() uint64 {
+	return s.failed
+}
+
+var tagEscaper = strings.NewReplacer(",", `\,`, "=", `\=`, " ", `\ `)
+
+// escapeTag escape tag value in line protocol, empty value is not allowed
+func escapeTag(v string) string {
+	if v == "" {
+		return "-"
 	}
+	return tagEscaper.Replace(v)
 }
 
 // Chan return the sink chan
@@ -177,31 +296,75 @@ func (s *InfluxDBSink) Chan() chan WriteResult {
 // Open open the influxdb sink
 func (s *InfluxDBSink) Open() {
 	s.ticker = time.NewTicker(time.Second)
-	err := s.client.Create("")
-	if err != nil {
-		panic(err)
-	}
-
+	s.wg.Add(1)
 	go s.run()
 }
 
-// Close close influxdb sink
+// Close close influxdb sink, results buffered are written before return
 func (s *InfluxDBSink) Close() {
+	close(s.Ch)
+	s.wg.Wait()
 }
 
 func (s *InfluxDBSink) run() {
+	defer s.wg.Done()
+	defer s.ticker.Stop()
 	for {
 		select {
 		case <-s.ticker.C:
-			// Write batch
-			s.client.Send(s.buf.Bytes(), 0)
-			s.buf.Reset()
-		case result := <-s.Ch:
-			// Add to batch
-			if result.Err != nil {
-				continue
+			s.flush()
+		case result, ok := <-s.Ch:
+			if !ok {
+				s.flush()
+				return
 			}
-			s.buf.WriteString(fmt.Sprintf("req,status=%v latNs=%v %v\n", result.StatusCode, result.LatNs, result.Timestamp))
+			s.add(result)
 		}
 	}
 }
+
+// add add result to batch, points of the request are recorded as written or failed
+func (s *InfluxDBSink) add(r WriteResult) {
+	var written, failed uint64
+	if r.OK() {
+		written = r.Points
+	} else {
+		failed = r.Points
+	}
+	fmt.Fprintf(s.buf, "req,op=%s,status=%s%s latNs=%d,written=%di,failed=%di %d\n",
+		r.Op, r.Status(), s.tags, r.LatNs, written, failed, r.Timestamp)
+}
+
+// flush write batch of results buffered
+func (s *InfluxDBSink) flush() {
+	if s.buf.Len() == 0 {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), StatsFlushTimeout)
+	defer cancel()
+	_, status, body, err := s.client.Send(ctx, s.buf.Bytes(), 0)
+	s.buf.Reset()
+	if err == nil && status >= 300 {
+		err = fmt.Errorf("status %d", status)
+	}
+	if err != nil {
+		s.failed++
+		s.logFailure(err, body, time.Now())
+	}
+}
+
+// logFailure log the failed flush, unless a failed flush is logged recently
+func (s *InfluxDBSink) logFailure(err error, body string, now time.Time) {
+	if now.Sub(s.logged) < ErrorLogInterval {
+		s.suppressed++
+		return
+	}
+	const timeFormat = "[2006-01-02 15:04:05]"
+	more := ""
+	if s.suppressed > 0 {
+		more = fmt.Sprintf(" (%d more since last logged)", s.suppressed)
+	}
+	sample := errorSample(WriteResult{Body: body, Err: err})
+	fmt.Fprintf(s.out, "%s stats-record flush failed%s: %s\n", now.Format(timeFormat), more, sample)
+	s.logged, s.suppressed = now, 0
+}
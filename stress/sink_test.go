@@ -0,0 +1,154 @@
+package stress
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/deltacat/dbstress/client"
+)
+
+func TestErrorSink_aggregate(t *testing.T) {
+	out := &bytes.Buffer{}
+	s := NewErrorSink(1, out)
+	now := time.Now()
+	busy := WriteResult{Op: OpInsert, Points: 100, StatusCode: 503, Body: "busy", Err: &client.StatusError{StatusCode: 503}}
+	for i := 0; i < 5; i++ {
+		s.aggregate(busy, now.Add(time.Duration(i)*time.Second))
+	}
+	s.aggregate(busy, now.Add(ErrorLogInterval))
+	conflict := WriteResult{Op: OpInsert, Points: 100, StatusCode: 400, Body: `{"error":"partial write: field type conflict"}`, Err: errors.New("Bad Request")}
+	s.aggregate(conflict, now)
+	s.aggregate(WriteResult{Op: OpQuery, Points: 1, Err: client.ErrTimeout, Retrying: true}, now)
+
+	classes := s.Classes()
+	if got, exp := len(classes), 3; got != exp {
+		t.Fatalf("Wrong number of classes. Got %v, Expected: %v\n%+v", got, exp, classes)
+	}
+	if c := classes[0]; c.Class != "http 503" || c.Count != 6 || c.Points != 600 || len(c.Samples) != 1 {
+		t.Errorf("Wrong first class %+v\n", c)
+	}
+	if c := classes[1]; c.Class != client.ClassFieldTypeConflict || c.Count != 1 {
+		t.Errorf("Wrong second class %+v\n", c)
+	}
+	if c := classes[2]; c.Op != OpQuery || c.Class != client.ClassTimeout || c.Retried != 1 {
+		t.Errorf("Wrong third class %+v\n", c)
+	}
+	// first of each class, and once more after the interval
+	if got, exp := strings.Count(out.String(), "\n"), 4; got != exp {
+		t.Errorf("Wrong number of logged lines. Got %v, Expected: %v\n%s", got, exp, out)
+	}
+	if !strings.Contains(out.String(), "(4 more since last logged)") {
+		t.Errorf("Suppressed failures should be counted in next log\n%s", out)
+	}
+}
+
+// stuckSink never takes results
+type stuckSink struct{ ch chan WriteResult }
+
+func (s *stuckSink) Chan() chan WriteResult { return s.ch }
+func (s *stuckSink) Open()                  {}
+func (s *stuckSink) Close()                 {}
+
+func TestMultiSink_dropped(t *testing.T) {
+	s := NewMultiSink(1)
+	latency := NewLatencySink(1)
+	s.AddSink("latency", latency)
+	s.AddSink("stuck", &stuckSink{ch: make(chan WriteResult, 2)})
+	s.Open()
+	for i := 0; i < 5; i++ {
+		s.Chan() <- WriteResult{Op: OpInsert, LatNs: int64(time.Millisecond), StatusCode: 204}
+	}
+	s.Close()
+
+	dropped := s.Dropped()
+	if got, exp := dropped["stuck"], uint64(3); got != exp {
+		t.Errorf("Wrong dropped of full queue. Got %v, Expected: %v\n", got, exp)
+	}
+	if _, ok := dropped["latency"]; ok {
+		t.Errorf("A slow sink should not make others drop. Got %v\n", dropped)
+	}
+	if got, exp := latency.Stats(OpInsert).Count, int64(5); got != exp {
+		t.Errorf("Wrong latency count. Got %v, Expected: %v\n", got, exp)
+	}
+}
+
+func TestWriteConfig_report_dropped(t *testing.T) {
+	var dropped uint64
+	rec := NewLatencyRecorder()
+	cfg := WriteConfig{Results: make(chan WriteResult, 1), Latency: rec, Dropped: &dropped}
+	for i := 0; i < 3; i++ {
+		cfg.report(WriteResult{Op: OpInsert, LatNs: int64(time.Millisecond), StatusCode: 204})
+	}
+	if dropped != 2 {
+		t.Errorf("Wrong dropped. Got %v, Expected: %v\n", dropped, 2)
+	}
+	if got, exp := rec.Stats(OpInsert).Count, int64(3); got != exp {
+		t.Errorf("Latency recorded in worker should be lossless. Got count %v, Expected: %v\n", got, exp)
+	}
+}
+
+// captureClient client capturing batches sent
+type captureClient struct {
+	client.Client
+	sent   []string
+	status int // status of sends, 204 if not set
+}
+
+func (c *captureClient) Send(ctx context.Context, b []byte, _ int) (int64, int, string, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		return 0, 0, "", errors.New("send without timeout")
+	}
+	c.sent = append(c.sent, string(b))
+	if c.status != 0 {
+		return 0, c.status, `{"error":"authorization failed"}`, nil
+	}
+	return 0, 204, "", nil
+}
+
+func TestInfluxDBSink(t *testing.T) {
+	cli := &captureClient{}
+	s := NewInfluxDBSink(1, cli, StatsTags{Case: "load 1", Connection: "db,a", BatchSize: 5000, Concurrency: 4}, ioutil.Discard)
+	s.Open()
+	s.Chan() <- WriteResult{Op: OpInsert, StatusCode: 204, LatNs: 1000, Points: 5000, Timestamp: 1}
+	s.Chan() <- WriteResult{Op: OpInsert, StatusCode: 503, Err: errors.New("busy"), LatNs: 2000, Points: 5000, Timestamp: 2}
+	s.Close()
+
+	exp := `req,op=insert,status=204,case=load\ 1,connection=db\,a,batch_size=5000,concurrency=4 latNs=1000,written=5000i,failed=0i 1
+req,op=insert,status=503,case=load\ 1,connection=db\,a,batch_size=5000,concurrency=4 latNs=2000,written=0i,failed=5000i 2
+`
+	if got := strings.Join(cli.sent, ""); got != exp {
+		t.Errorf("Wrong stats recorded. Got:\n%s\nExpected:\n%s\n", got, exp)
+	}
+	if s.Failed() != 0 {
+		t.Errorf("Expected no failed flush, got %d\n", s.Failed())
+	}
+}
+
+func TestInfluxDBSink_failed(t *testing.T) {
+	cli := &captureClient{status: 401}
+	out := &bytes.Buffer{}
+	s := NewInfluxDBSink(1, cli, StatsTags{Case: "c"}, out)
+	for i := 0; i < 3; i++ {
+		s.add(WriteResult{Op: OpInsert, StatusCode: 204})
+		s.flush()
+	}
+	if got, exp := s.Failed(), uint64(3); got != exp {
+		t.Errorf("Wrong failed flushes. Got %d, Expected: %d\n", got, exp)
+	}
+	if got, exp := strings.Count(out.String(), "stats-record flush failed"), 1; got != exp {
+		t.Errorf("Failed flushes should be logged rate limited. Got %d lines:\n%s\n", got, out.String())
+	}
+	if !strings.Contains(out.String(), "authorization failed") {
+		t.Errorf("Failed flush should be logged with body. Got:\n%s\n", out.String())
+	}
+
+	s.logFailure(errors.New("status 401"), "", time.Now().Add(ErrorLogInterval))
+	if !strings.Contains(out.String(), "(2 more since last logged)") {
+		t.Errorf("Suppressed failures should be noted. Got:\n%s\n", out.String())
+	}
+}
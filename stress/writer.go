@@ -3,24 +3,62 @@ package stress
 import (
 	"bytes"
 	"compress/gzip"
+	"context"
 	"io"
+	"strconv"
+	"sync/atomic"
 	"time"
 
 	"github.com/deltacat/dbstress/client"
 	"github.com/deltacat/dbstress/data/influx/lineprotocol"
 	"github.com/deltacat/dbstress/data/mysql"
+	"github.com/deltacat/dbstress/data/postgres"
+)
+
+// result status of requests without status code
+const (
+	StatusTimeout = "timeout"
+	StatusError   = "error"
+)
+
+// operation types of results
+const (
+	OpInsert = "insert"
+	OpCopy   = "copy"
+	OpQuery  = "query"
 )
 
 // WriteResult contains the latency, status code, and error type
-// each time a write happens.
+// each time a write (or query) happens.
 type WriteResult struct {
+	Op         string // operation type
+	Points     uint64 // points in the batch, 1 for a query
 	LatNs      int64
 	StatusCode int
 	Body       string // Only populated when unusual status code encountered.
 	Err        error
+	Timeout    bool // not answered within timeout of the connection
+	Attempt    int  // 1 for the first try, more for retries
+	Retrying   bool // failed attempt which is going to be retried
 	Timestamp  int64
 }
 
+// OK check if the write (or query) succeeded, writes answer 204, queries answer 200
+func (r WriteResult) OK() bool {
+	return r.Err == nil && r.StatusCode >= 200 && r.StatusCode <= 299
+}
+
+// Status return status code of the result as text, "timeout" if timed out, "error" if no response
+func (r WriteResult) Status() string {
+	switch {
+	case r.Timeout:
+		return StatusTimeout
+	case r.StatusCode == 0:
+		return StatusError
+	}
+	return strconv.Itoa(r.StatusCode)
+}
+
 // WriteConfig specifies the configuration for the Write function.
 type WriteConfig struct {
 	BatchSize uint64
@@ -33,13 +71,124 @@ type WriteConfig struct {
 	Deadline time.Time
 	Tick     <-chan time.Time
 	Results  chan<- WriteResult
+
+	// If set, batches are throttled by the limiter instead of Tick.
+	Limiter *RateLimiter
+
+	// If set along with Limiter, batches are scheduled on a fixed timeline of the target rate
+	// and latency is measured from the intended send time instead of from the request start,
+	// so time spent queued behind a stalled target is not omitted.
+	OpenLoop bool
+
+	// If set, requests in flight are counted here.
+	InFlight *int64
+
+	// If set, latency is recorded here by the worker before results are sent, so none is lost.
+	Latency *LatencyRecorder
+	// If set, results dropped as Results is full are counted here.
+	Dropped *uint64
+
+	// If set, failed batches are retried following the policy.
+	Retry *RetryPolicy
+	// If set, points written after retries and retries sent are counted here.
+	Retried *uint64
+	Retries *uint64
+
+	// If set, requests are counted against the failure budget by the worker, so none is missed.
+	Budget *BudgetCounter
+}
+
+// acquire blocks until a batch of n points is allowed by the limiter,
+// returns the intended send time in open loop mode (zero otherwise),
+// and false if the deadline passed or ctx is done while waiting
+func (cfg *WriteConfig) acquire(ctx context.Context, n uint64) (time.Time, bool) {
+	if cfg.Limiter == nil {
+		return time.Time{}, ctx.Err() == nil
+	}
+	if !cfg.OpenLoop {
+		return time.Time{}, cfg.Limiter.Wait(ctx, n, cfg.Deadline)
+	}
+	at, ok := cfg.Limiter.Schedule(n)
+	for !ok {
+		if !pause(ctx, cfg.Deadline) {
+			return at, false
+		}
+		at, ok = cfg.Limiter.Schedule(n)
+	}
+	if at.After(cfg.Deadline) {
+		sleepUntil(ctx, cfg.Deadline)
+		return at, false
+	}
+	return at, sleepUntil(ctx, at)
+}
+
+// sleepUntil sleep until t, return false if ctx is done before that
+func sleepUntil(ctx context.Context, t time.Time) bool {
+	timer := time.NewTimer(time.Until(t))
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// begin count a request in flight, the returned func should be called when it is done
+func (cfg *WriteConfig) begin() func() {
+	if cfg.InFlight == nil {
+		return func() {}
+	}
+	atomic.AddInt64(cfg.InFlight, 1)
+	return func() { atomic.AddInt64(cfg.InFlight, -1) }
+}
+
+// report count result against the budget and send it to Results, the result is dropped if Results is full
+func (cfg *WriteConfig) report(r WriteResult) {
+	r.Timestamp = time.Now().UnixNano()
+	r.Timeout = client.IsTimeout(r.Err)
+	if cfg.Budget != nil {
+		cfg.Budget.Count(r)
+	}
+	if cfg.Latency != nil {
+		cfg.Latency.Record(r)
+	}
+	select {
+	case cfg.Results <- r:
+	default:
+		if cfg.Dropped != nil {
+			atomic.AddUint64(cfg.Dropped, 1)
+		}
+	}
+}
+
+// queuedNs return how long an attempt started later than origin of its request, 0 if not later
+func queuedNs(intended, start time.Time) int64 {
+	if intended.IsZero() || !start.After(intended) {
+		return 0
+	}
+	return start.Sub(intended).Nanoseconds()
+}
+
+// next blocks until next tick, unless throttled by the limiter, return false if ctx is done
+func (cfg *WriteConfig) next(ctx context.Context) (time.Time, bool) {
+	if cfg.Limiter != nil {
+		return time.Now(), ctx.Err() == nil
+	}
+	select {
+	case t := <-cfg.Tick:
+		return t, true
+	case <-ctx.Done():
+		return time.Now(), false
+	}
 }
 
 // WriteInflux takes in a slice of lineprotocol.Points, a write.Client, and a WriteConfig. It will attempt
 // to write data to the target until one of the following conditions is met.
 // 1. We reach that MaxPoints specified in the WriteConfig.
 // 2. We've passed the Deadline specified in the WriteConfig.
-func WriteInflux(pts []lineprotocol.Point, c client.Client, cfg WriteConfig) (uint64, uint64, time.Duration) {
+// 3. ctx is done.
+func WriteInflux(ctx context.Context, pts []lineprotocol.Point, c client.Client, cfg WriteConfig) (uint64, uint64, time.Duration) {
 	if cfg.Results == nil {
 		panic("Results Channel on WriteConfig cannot be nil")
 	}
@@ -86,7 +235,12 @@ WRITE_BATCHES:
 						panic(err)
 					}
 				}
-				if err := sendBatchInflux(c, buf, cfg.GzipLevel, cfg.Results); err != nil {
+				intended, ok := cfg.acquire(ctx, cfg.BatchSize)
+				if !ok {
+					pointCount -= cfg.BatchSize
+					break WRITE_BATCHES
+				}
+				if err := sendBatchInflux(ctx, c, buf, &cfg, cfg.BatchSize, intended); err != nil {
 					failedCount += cfg.BatchSize
 				}
 
@@ -96,8 +250,7 @@ WRITE_BATCHES:
 					gzw.Reset(buf)
 				}
 
-				t = <-cfg.Tick
-				if t.After(cfg.Deadline) {
+				if t, ok = cfg.next(ctx); !ok || t.After(cfg.Deadline) {
 					break WRITE_BATCHES
 				}
 
@@ -120,21 +273,22 @@ WRITE_BATCHES:
 	return pointCount, failedCount, time.Since(start)
 }
 
-func sendBatchInflux(c client.Client, buf *bytes.Buffer, gzip int, ch chan<- WriteResult) error {
-	lat, status, body, err := c.Send(buf.Bytes(), gzip)
-	buf.Reset()
-	select {
-	case ch <- WriteResult{LatNs: lat, StatusCode: status, Body: body, Err: err, Timestamp: time.Now().UnixNano()}:
-	default:
-	}
-	return err
+func sendBatchInflux(ctx context.Context, c client.Client, buf *bytes.Buffer, cfg *WriteConfig, points uint64, intended time.Time) error {
+	defer buf.Reset()
+	return cfg.send(ctx, intended, func() WriteResult {
+		done := cfg.begin()
+		lat, status, body, err := c.Send(ctx, buf.Bytes(), cfg.GzipLevel)
+		done()
+		return WriteResult{Op: OpInsert, Points: points, LatNs: lat, StatusCode: status, Body: body, Err: err}
+	})
 }
 
 // WriteMySQL writes rows into mysql.
 // Simlar as influx processing, it will attempt to write data to the target until one of the following conditions is met.
 // 1. We reach that MaxPoints specified in the WriteConfig.
 // 2. We've passed the Deadline specified in the WriteConfig.
-func WriteMySQL(table mysql.TableChunk, c client.Client, cfg WriteConfig) (uint64, uint64, time.Duration) {
+// 3. ctx is done.
+func WriteMySQL(ctx context.Context, table mysql.TableChunk, c client.Client, cfg WriteConfig) (uint64, uint64, time.Duration) {
 	if cfg.Results == nil {
 		panic("Results Channel on WriteConfig cannot be nil")
 	}
@@ -151,12 +305,18 @@ func WriteMySQL(table mysql.TableChunk, c client.Client, cfg WriteConfig) (uint6
 		if t.After(cfg.Deadline) || pointCount >= cfg.MaxPoints {
 			break
 		}
+		intended, ok := cfg.acquire(ctx, table.GetRowsNum())
+		if !ok {
+			break
+		}
 		pointCount += table.GetRowsNum()
 
-		if err := sendBatchMySQL(c, table.GenInsertStmt(), cfg.Results); err != nil {
+		if err := sendBatchMySQL(ctx, c, table.GenInsertStmt(), &cfg, table.GetRowsNum(), intended); err != nil {
 			failedCount += table.GetRowsNum()
 		}
-		t = <-cfg.Tick
+		if t, ok = cfg.next(ctx); !ok {
+			break
+		}
 
 		// Avoid timestamp colision when batch size > pts
 		if t.After(tPrev) {
@@ -169,11 +329,72 @@ func WriteMySQL(table mysql.TableChunk, c client.Client, cfg WriteConfig) (uint6
 	return pointCount, failedCount, time.Since(start)
 }
 
-func sendBatchMySQL(c client.Client, query string, ch chan<- WriteResult) error {
-	lat, status, body, err := c.SendString(query)
-	select {
-	case ch <- WriteResult{LatNs: lat, StatusCode: status, Body: body, Err: err, Timestamp: time.Now().UnixNano()}:
-	default:
+func sendBatchMySQL(ctx context.Context, c client.Client, query string, cfg *WriteConfig, points uint64, intended time.Time) error {
+	return cfg.send(ctx, intended, func() WriteResult {
+		done := cfg.begin()
+		lat, status, body, err := c.SendString(ctx, query)
+		done()
+		return WriteResult{Op: OpInsert, Points: points, LatNs: lat, StatusCode: status, Body: body, Err: err}
+	})
+}
+
+// WritePostgres writes rows into postgres, either by multi-row INSERT or by COPY FROM STDIN.
+// Simlar as mysql processing, it will attempt to write data to the target until one of the following conditions is met.
+// 1. We reach that MaxPoints specified in the WriteConfig.
+// 2. We've passed the Deadline specified in the WriteConfig.
+// 3. ctx is done.
+func WritePostgres(ctx context.Context, table postgres.TableChunk, c client.Client, cfg WriteConfig, copyFrom bool) (uint64, uint64, time.Duration) {
+	if cfg.Results == nil {
+		panic("Results Channel on WriteConfig cannot be nil")
 	}
-	return err
+	var pointCount uint64
+	var failedCount uint64
+
+	start := time.Now()
+	t := time.Now()
+
+	tPrev := t
+	for {
+		table.Update()
+
+		if t.After(cfg.Deadline) || pointCount >= cfg.MaxPoints {
+			break
+		}
+		intended, ok := cfg.acquire(ctx, table.GetRowsNum())
+		if !ok {
+			break
+		}
+		pointCount += table.GetRowsNum()
+
+		var err error
+		if copyFrom {
+			err = sendBatchPostgresCopy(ctx, c, table.GenCopyData(), &cfg, table.GetRowsNum(), intended)
+		} else {
+			err = sendBatchMySQL(ctx, c, table.GenInsertStmt(), &cfg, table.GetRowsNum(), intended)
+		}
+		if err != nil {
+			failedCount += table.GetRowsNum()
+		}
+		if t, ok = cfg.next(ctx); !ok {
+			break
+		}
+
+		// Avoid timestamp colision when batch size > pts
+		if t.After(tPrev) {
+			tPrev = t
+			continue
+		}
+		t = t.Add(1 * time.Nanosecond)
+	}
+
+	return pointCount, failedCount, time.Since(start)
+}
+
+func sendBatchPostgresCopy(ctx context.Context, c client.Client, data []byte, cfg *WriteConfig, points uint64, intended time.Time) error {
+	return cfg.send(ctx, intended, func() WriteResult {
+		done := cfg.begin()
+		lat, status, body, err := c.Send(ctx, data, 0)
+		done()
+		return WriteResult{Op: OpCopy, Points: points, LatNs: lat, StatusCode: status, Body: body, Err: err}
+	})
 }
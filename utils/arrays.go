@@ -11,3 +11,13 @@ func ArrayContainsStringIgnoreCase(src []string, target string) bool {
 	}
 	return false
 }
+
+// ArrayContainsString check if a string array contains target string
+func ArrayContainsString(src []string, target string) bool {
+	for _, s := range src {
+		if s == target {
+			return true
+		}
+	}
+	return false
+}